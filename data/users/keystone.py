@@ -4,7 +4,7 @@ import os
 from keystoneauth1.identity import v2 as keystone_v2_auth
 from keystoneauth1.identity import v3 as keystone_v3_auth
 from keystoneauth1 import session
-from keystoneauth1.exceptions import ClientException
+from keystoneauth1.exceptions import ClientException, ConnectFailure
 from keystoneclient.v2_0 import client as client_v2
 from keystoneclient.v3 import client as client_v3
 from keystoneclient.exceptions import AuthorizationFailure as KeystoneAuthorizationFailure
@@ -84,6 +84,9 @@ class KeystoneV2Users(FederatedUsers):
         except KeystoneUnauthorized as kut:
             logger.exception("Keystone unauthorized admin")
             return (False, "Keystone admin credentials are invalid: %s" % str(kut))
+        except ConnectFailure as cf:
+            logger.exception("Keystone unreachable")
+            return (False, "Could not connect to Keystone: %s" % str(cf))
         except ClientException as e:
             logger.exception("Keystone unauthorized admin")
             return (False, "Keystone ping check failed: %s" % str(e))
@@ -202,6 +205,9 @@ class KeystoneV3Users(FederatedUsers):
         except KeystoneUnauthorized as kut:
             logger.exception("Keystone unauthorized admin")
             return (False, "Keystone admin credentials are invalid: %s" % str(kut))
+        except ConnectFailure as cf:
+            logger.exception("Keystone unreachable")
+            return (False, "Could not connect to Keystone: %s" % str(cf))
         except ClientException as cle:
             logger.exception("Keystone unauthorized admin")
             return (False, "Keystone ping check failed: %s" % str(cle))
@@ -23,6 +23,7 @@ logger = logging.getLogger(__name__)
 
 
 OIDC_WELLKNOWN = ".well-known/openid-configuration"
+OIDC_REQUIRED_ENDPOINTS = ["authorization_endpoint", "token_endpoint", "jwks_uri"]
 PUBLIC_KEY_CACHE_TTL = 3600  # 1 hour
 ALLOWED_ALGORITHMS = ["RS256"]
 JWT_CLOCK_SKEW_SECONDS = 30
@@ -112,6 +113,23 @@ class OIDCLoginService(OAuthService):
         )
 
     def validate(self):
+        # Ensure the discovery information names every endpoint needed to login.
+        oidc_config = self._oidc_config()
+        missing = [key for key in OIDC_REQUIRED_ENDPOINTS if not oidc_config.get(key)]
+        if missing:
+            msg = "OIDC discovery information is missing %s" % ", ".join(missing)
+            raise DiscoveryFailureException(msg)
+
+        for endpoint_key in OIDC_REQUIRED_ENDPOINTS:
+            endpoint = oidc_config[endpoint_key]
+            (scheme, netloc, _, _, _) = urllib.parse.urlsplit(str(endpoint))
+            if scheme not in ("http", "https") or not netloc:
+                msg = "OIDC discovery information has an invalid %s: `%s`" % (
+                    endpoint_key,
+                    endpoint,
+                )
+                raise DiscoveryFailureException(msg)
+
         return bool(self.get_login_scopes())
 
     def validate_client_id_and_secret(self, http_client, url_scheme_and_hostname):
@@ -120,6 +138,42 @@ class OIDCLoginService(OAuthService):
         if check_auth_url.status_code // 100 != 2:
             raise Exception("Got non-200 status code for authorization endpoint")
 
+    def validate_client_credentials(self, http_client):
+        """
+        Verifies the client ID and secret by requesting a token from the token endpoint with the
+        client credentials grant.
+
+        Returns None if a token was issued. Otherwise, returns the OAuth `error` code given by the
+        token endpoint, such as `invalid_client` for a bad client ID or secret, or a description of
+        the HTTP failure if the response carried no error code.
+        """
+        data = {
+            "client_id": self.client_id(),
+            "client_secret": self.client_secret(),
+            "grant_type": "client_credentials",
+        }
+        headers = {"Accept": "application/json"}
+
+        result = http_client.post(
+            self.token_endpoint().to_url(), data=data, headers=headers, timeout=5
+        )
+        if result.status_code // 100 == 2:
+            return None
+
+        try:
+            error = result.json().get("error")
+        except (ValueError, AttributeError):
+            error = None
+
+        if error:
+            return error
+
+        # Per RFC 6749, a client that fails HTTP authentication is answered with a 401.
+        if result.status_code == 401:
+            return "invalid_client"
+
+        return "HTTP %s" % result.status_code
+
     def requires_form_encoding(self):
         return True
 
@@ -250,6 +304,10 @@ class OIDCLoginService(OAuthService):
 
         discovery_url = urllib.parse.urljoin(oidc_server, OIDC_WELLKNOWN)
         discovery = self._http_client.get(discovery_url, timeout=5, verify=is_debugging is False)
+        if discovery.status_code == 404:
+            msg = "OIDC discovery information not found at %s; check that OIDC_SERVER is the issuer"
+            raise DiscoveryFailureException(msg % discovery_url)
+
         if discovery.status_code // 100 != 2:
             logger.debug(
                 "Got %s response for OIDC discovery: %s", discovery.status_code, discovery.text
@@ -257,11 +315,16 @@ class OIDCLoginService(OAuthService):
             raise DiscoveryFailureException("Could not load OIDC discovery information")
 
         try:
-            return json.loads(discovery.text)
+            oidc_config = json.loads(discovery.text)
         except ValueError:
             logger.exception("Could not parse OIDC discovery for url: %s", discovery_url)
             raise DiscoveryFailureException("Could not parse OIDC discovery information")
 
+        if not isinstance(oidc_config, dict):
+            raise DiscoveryFailureException("OIDC discovery information is not a JSON object")
+
+        return oidc_config
+
     def decode_user_jwt(self, token):
         """
         Decodes the given JWT under the given provider and returns it.
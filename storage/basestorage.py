@@ -1,3 +1,4 @@
+import hashlib
 import logging
 import tempfile
 
@@ -51,10 +52,16 @@ class BaseStorage(StoragePaths):
         The client is an HTTP client to use for any external calls.
         """
         # Put a temporary file to make sure the normal storage paths work.
-        self.put_content("_verify", b"testing 123")
+        verification_contents = b"testing 123"
+        self.put_content("_verify", verification_contents)
         if not self.exists("_verify"):
             raise Exception("Could not find verification file")
 
+        # Read the file back to make sure the stored contents are intact.
+        expected_hash = hashlib.sha256(verification_contents).hexdigest()
+        if hashlib.sha256(self.get_content("_verify")).hexdigest() != expected_hash:
+            raise Exception("Verification file contents do not match the contents written")
+
     def get_direct_download_url(
         self, path, request_ip=None, expires_in=60, requires_cors=False, head=False
     ):
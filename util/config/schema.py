@@ -150,7 +150,7 @@ CONFIG_SCHEMA = {
         "SSL_PROTOCOLS": {
             "type": "array",
             "description": "If specified, the nginx-defined list of SSL protocols to enabled and disabled",
-            "x-example": ["TLSv1.1", "TLSv1.2"],
+            "x-example": ["TLSv1.2", "TLSv1.3"],
             "x-reference": "http://nginx.org/en/docs/http/ngx_http_ssl_module.html#ssl_protocols",
         },
         # User-visible configuration.
@@ -619,7 +619,7 @@ CONFIG_SCHEMA = {
             "type": "string",
             "description": "The length of time a token for recovering a user accounts is valid. Defaults to 30m.",
             "x-example": "10m",
-            "pattern": "^[0-9]+(w|m|d|h|s)$",
+            "pattern": "^([0-9]+(w|m|d|h|s))+$",
         },
         "SESSION_COOKIE_SECURE": {
             "type": "boolean",
@@ -669,14 +669,14 @@ CONFIG_SCHEMA = {
         "DEFAULT_TAG_EXPIRATION": {
             "type": "string",
             "description": "The default, configurable tag expiration time for time machine. Defaults to `2w`.",
-            "pattern": "^[0-9]+(w|m|d|h|s)$",
+            "pattern": "^([0-9]+(w|m|d|h|s))+$",
         },
         "TAG_EXPIRATION_OPTIONS": {
             "type": "array",
             "description": "The options that users can select for expiration of tags in their namespace (if enabled)",
             "items": {
                 "type": "string",
-                "pattern": "^[0-9]+(w|m|d|h|s)$",
+                "pattern": "^([0-9]+(w|m|d|h|s))+$",
             },
         },
         # Team syncing.
@@ -689,7 +689,7 @@ CONFIG_SCHEMA = {
             "type": "string",
             "description": "If team syncing is enabled for a team, how often to check its membership and resync if necessary (Default: 30m)",
             "x-example": "2h",
-            "pattern": "^[0-9]+(w|m|d|h|s)$",
+            "pattern": "^([0-9]+(w|m|d|h|s))+$",
         },
         "FEATURE_NONSUPERUSER_TEAM_SYNCING_SETUP": {
             "type": "boolean",
@@ -807,12 +807,12 @@ CONFIG_SCHEMA = {
         "APP_SPECIFIC_TOKEN_EXPIRATION": {
             "type": ["string", "null"],
             "description": "The expiration for external app tokens. Defaults to None.",
-            "pattern": "^[0-9]+(w|m|d|h|s)$",
+            "pattern": "^([0-9]+(w|m|d|h|s))+$",
         },
         "EXPIRED_APP_SPECIFIC_TOKEN_GC": {
             "type": ["string", "null"],
             "description": "Duration of time expired external app tokens will remain before being garbage collected. Defaults to 1d.",
-            "pattern": "^[0-9]+(w|m|d|h|s)$",
+            "pattern": "^([0-9]+(w|m|d|h|s))+$",
         },
         # Feature Flag: Garbage collection.
         "FEATURE_GARBAGE_COLLECTION": {
@@ -1156,6 +1156,12 @@ CONFIG_SCHEMA = {
             "description": "The time after which a fresh login requires users to reenter their password",
             "x-example": "5m",
         },
+        "SESSION_TIMEOUT": {
+            "type": "string",
+            "description": "The time after which a permanent login session expires. Defaults to 31d.",
+            "x-example": "7d",
+            "pattern": "^([0-9]+(w|m|d|h|s))+$",
+        },
         # Webhook blacklist.
         "WEBHOOK_HOSTNAME_BLACKLIST": {
             "type": "array",
@@ -2,6 +2,9 @@ import pytest
 
 from util.config.validator import is_valid_config_upload_filename
 from util.config.validator import CONFIG_FILENAMES, CONFIG_FILE_SUFFIXES
+from util.config.validator import ValidatorContext, validate_service_for_config
+
+from test.fixtures import *
 
 
 def test_valid_config_upload_filenames():
@@ -30,3 +33,18 @@ def test_valid_config_upload_filenames():
 )
 def test_is_valid_config_upload_filename(filename, expect_valid):
     assert is_valid_config_upload_filename(filename) == expect_valid
+
+
+def test_validate_service_for_config_warnings(app):
+    validator_context = ValidatorContext(
+        {
+            "DISTRIBUTED_STORAGE_CONFIG": {"local": ["FakeStorage", {}]},
+            "DISTRIBUTED_STORAGE_PREFERENCE": ["local", "local"],
+        }
+    )
+
+    result = validate_service_for_config("registry-storage", validator_context)
+    assert result["status"]
+    assert result["warnings"] == [
+        "Duplicate storage location(s) in DISTRIBUTED_STORAGE_PREFERENCE: local"
+    ]
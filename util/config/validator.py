@@ -12,7 +12,12 @@ from util.config.validators.validate_ldap import LDAPValidator
 from util.config.validators.validate_keystone import KeystoneValidator
 from util.config.validators.validate_jwt import JWTAuthValidator
 from util.config.validators.validate_secscan import SecurityScannerValidator
-from util.config.validators.validate_ssl import SSLValidator, SSL_FILENAMES
+from util.config.validators.validate_ssl import (
+    SSLValidator,
+    SSL_FILENAMES,
+    EXTRA_CA_DIRECTORY,
+    EXTRA_CA_DIRECTORY_PREFIX,
+)
 from util.config.validators.validate_google_login import GoogleLoginValidator
 from util.config.validators.validate_bitbucket_trigger import BitbucketTriggerValidator
 from util.config.validators.validate_gitlab_trigger import GitLabTriggerValidator
@@ -24,6 +29,9 @@ from util.config.validators.validate_actionlog_archiving import ActionLogArchivi
 from util.config.validators.validate_apptokenauth import AppTokenAuthValidator
 from util.config.validators.validate_elasticsearch import ElasticsearchValidator
 from util.config.validators.validate_kinesis import KinesisValidator
+from util.config.validators.validate_kafka import KafkaValidator
+from util.config.validators.validate_email import EmailValidator
+from util.config.validators.validate_secret_keys import SecretKeyValidator
 
 logger = logging.getLogger(__name__)
 
@@ -45,8 +53,6 @@ CONFIG_FILENAMES = (
     SSL_FILENAMES + DB_SSL_FILENAMES + JWT_FILENAMES + ACI_CERT_FILENAMES + LDAP_FILENAMES
 )
 CONFIG_FILE_SUFFIXES = ["-cloudfront-signing-key.pem"]
-EXTRA_CA_DIRECTORY = "extra_ca_certs"
-EXTRA_CA_DIRECTORY_PREFIX = "extra_ca_certs_"
 
 VALIDATORS = {
     DatabaseValidator.name: DatabaseValidator.validate,
@@ -69,6 +75,9 @@ VALIDATORS = {
     AppTokenAuthValidator.name: AppTokenAuthValidator.validate,
     ElasticsearchValidator.name: ElasticsearchValidator.validate,
     KinesisValidator.name: KinesisValidator.validate,
+    KafkaValidator.name: KafkaValidator.validate,
+    EmailValidator.name: EmailValidator.validate,
+    SecretKeyValidator.name: SecretKeyValidator.validate,
 }
 
 
@@ -81,7 +90,11 @@ def validate_service_for_config(service, validator_context):
 
     try:
         VALIDATORS[service](validator_context)
-        return {"status": True}
+        result = {"status": True}
+        if validator_context.warnings:
+            result["warnings"] = list(validator_context.warnings)
+
+        return result
     except Exception as ex:
         logger.exception("Validation exception")
         return {"status": False, "reason": str(ex)}
@@ -135,6 +148,14 @@ class ValidatorContext(object):
         self.config_provider = config_provider
         self.instance_keys = instance_keys
         self.init_scripts_location = init_scripts_location
+        self.warnings = []
+
+    def add_warning(self, message):
+        """
+        Records a non-fatal validation problem, which is returned alongside a successful validation
+        status rather than failing it.
+        """
+        self.warnings.append(message)
 
     @classmethod
     def from_app(
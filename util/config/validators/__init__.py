@@ -1,5 +1,11 @@
+import base64
+import binascii
+import codecs
+import ipaddress
+
 from abc import ABCMeta, abstractmethod, abstractproperty
 from six import add_metaclass
+from urllib.parse import urlparse
 
 
 class ConfigValidationException(Exception):
@@ -26,3 +32,83 @@ class BaseValidator(object):
         Raises Exception if failure to validate.
         """
         pass
+
+
+def warn_if_internal_hostname(validator_context, service_name):
+    """
+    Adds a warning to the validator context if Quay's server hostname is a loopback, private or
+    link-local address, as the given external service will then be unable to deliver webhooks back
+    to Quay.
+    """
+    url_scheme_and_hostname = validator_context.url_scheme_and_hostname
+    if url_scheme_and_hostname is None:
+        return
+
+    hostname = url_scheme_and_hostname.hostname
+    try:
+        host = ipaddress.ip_address(hostname)
+    except ValueError:
+        host = urlparse("//" + hostname).hostname or ""
+        try:
+            host = ipaddress.ip_address(host)
+        except ValueError:
+            pass
+
+    if isinstance(host, str):
+        internal = host == "localhost" or host.endswith(".localhost")
+    else:
+        internal = host.is_loopback or host.is_private or host.is_link_local
+
+    if internal:
+        validator_context.add_warning(
+            "Server hostname `%s` appears to be internal-only; %s will be unable to reach Quay's "
+            "webhooks" % (hostname, service_name)
+        )
+
+
+_BYTE_ORDER_MARKS = [
+    (codecs.BOM_UTF8, "UTF-8"),
+    (codecs.BOM_UTF16_LE, "UTF-16"),
+    (codecs.BOM_UTF16_BE, "UTF-16"),
+]
+
+
+def validate_text_encoding(filename, contents):
+    """
+    Raises a ConfigValidationException if the given file contents (bytes) start with a byte order
+    mark or are not valid UTF-8, both of which commonly break the parsing of pasted PEM data.
+    """
+    for bom, encoding in _BYTE_ORDER_MARKS:
+        if contents.startswith(bom):
+            msg = "%s starts with a %s byte order mark; re-save it as UTF-8 without a BOM" % (
+                filename,
+                encoding,
+            )
+            raise ConfigValidationException(msg)
+
+    try:
+        contents.decode("utf-8")
+    except UnicodeDecodeError as ude:
+        msg = "%s contains non-UTF-8 data at byte %s; re-save it as UTF-8" % (filename, ude.start)
+        raise ConfigValidationException(msg)
+
+
+def validate_base64(name, value):
+    """
+    Decodes the given standard base64 value, raising a ConfigValidationException that describes the
+    paste error if it cannot be decoded.
+    """
+    if value != "".join(value.split()):
+        msg = "%s contains whitespace; remove any spaces or newlines" % name
+        raise ConfigValidationException(msg)
+
+    try:
+        return base64.b64decode(value, validate=True)
+    except binascii.Error as be:
+        try:
+            base64.b64decode(value, altchars=b"-_", validate=True)
+        except binascii.Error:
+            raise ConfigValidationException("%s is not valid base64: %s" % (name, be))
+
+        msg = "%s is URL-safe base64; replace `-` with `+` and `_` with `/`" % name
+        raise ConfigValidationException(msg)
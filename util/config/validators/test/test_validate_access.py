@@ -33,3 +33,38 @@ def test_validate_invalid_oidc_login_config(unvalidated_config, expected_excepti
             validator.validate(ValidatorContext(unvalidated_config))
     else:
         validator.validate(ValidatorContext(unvalidated_config))
+
+
+@pytest.mark.parametrize(
+    "unvalidated_config, expected_warnings",
+    [
+        ({}, []),
+        ({"FRESH_LOGIN_TIMEOUT": "1h", "SESSION_TIMEOUT": "1d"}, []),
+        (
+            {"FRESH_LOGIN_TIMEOUT": "2d", "SESSION_TIMEOUT": "1d"},
+            [
+                "FRESH_LOGIN_TIMEOUT (2d) is longer than SESSION_TIMEOUT (1d), so sessions will "
+                + "expire before a fresh login is ever required"
+            ],
+        ),
+        (
+            {
+                "FRESH_LOGIN_TIMEOUT": "2d",
+                "SESSION_TIMEOUT": "1d",
+                "FEATURE_PERMANENT_SESSIONS": False,
+            },
+            [],
+        ),
+    ],
+)
+def test_validate_session_timeouts(unvalidated_config, expected_warnings, app):
+    validator_context = ValidatorContext(unvalidated_config)
+    AccessSettingsValidator.validate(validator_context)
+    assert validator_context.warnings == expected_warnings
+
+
+def test_validate_invalid_session_timeout(app):
+    with pytest.raises(ConfigValidationException) as cve:
+        AccessSettingsValidator.validate(ValidatorContext({"SESSION_TIMEOUT": "1 month"}))
+
+    assert str(cve.value).startswith("Invalid SESSION_TIMEOUT `1 month`: expected ")
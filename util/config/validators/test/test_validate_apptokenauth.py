@@ -19,6 +19,22 @@ from test.fixtures import *
                 "FEATURE_DIRECT_LOGIN": True,
             }
         ),
+        (
+            {
+                "AUTHENTICATION_TYPE": "AppToken",
+                "FEATURE_APP_SPECIFIC_TOKENS": True,
+                "FEATURE_DIRECT_LOGIN": False,
+                "APP_SPECIFIC_TOKEN_EXPIRATION": "thirty days",
+            }
+        ),
+        (
+            {
+                "AUTHENTICATION_TYPE": "AppToken",
+                "FEATURE_APP_SPECIFIC_TOKENS": True,
+                "FEATURE_DIRECT_LOGIN": False,
+                "APP_SPECIFIC_TOKEN_EXPIRATION": "0d",
+            }
+        ),
     ],
 )
 def test_validate_invalid_auth_config(unvalidated_config, app):
@@ -29,6 +45,21 @@ def test_validate_invalid_auth_config(unvalidated_config, app):
 
 
 def test_validate_auth(app):
+    config = ValidatorContext(
+        {
+            "AUTHENTICATION_TYPE": "AppToken",
+            "FEATURE_APP_SPECIFIC_TOKENS": True,
+            "FEATURE_DIRECT_LOGIN": False,
+            "APP_SPECIFIC_TOKEN_EXPIRATION": "30d",
+        }
+    )
+
+    validator = AppTokenAuthValidator()
+    validator.validate(config)
+    assert not config.warnings
+
+
+def test_validate_auth_without_expiration(app):
     config = ValidatorContext(
         {
             "AUTHENTICATION_TYPE": "AppToken",
@@ -39,3 +70,5 @@ def test_validate_auth(app):
 
     validator = AppTokenAuthValidator()
     validator.validate(config)
+    assert len(config.warnings) == 1
+    assert "APP_SPECIFIC_TOKEN_EXPIRATION" in config.warnings[0]
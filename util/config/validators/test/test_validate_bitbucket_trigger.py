@@ -1,4 +1,5 @@
 import pytest
+import requests
 
 from httmock import urlmatch, HTTMock
 
@@ -54,3 +55,47 @@ def test_validate_bitbucket_trigger(app):
         validator.validate(unvalidated_config)
 
         assert url_hit[0]
+
+
+def test_validate_bitbucket_trigger_invalid_credentials(app):
+    @urlmatch(netloc=r"bitbucket.org")
+    def handler(url, request):
+        return {"status_code": 401, "content": "Unknown consumer key"}
+
+    with HTTMock(handler):
+        unvalidated_config = ValidatorContext(
+            {
+                "BITBUCKET_TRIGGER_CONFIG": {
+                    "CONSUMER_KEY": "foo",
+                    "CONSUMER_SECRET": "bar",
+                },
+            },
+            url_scheme_and_hostname=URLSchemeAndHostname("https", "quay.example.com"),
+        )
+
+        with pytest.raises(ConfigValidationException) as cve:
+            BitbucketTriggerValidator.validate(unvalidated_config)
+
+    assert str(cve.value).startswith("Invalid consumer key or secret")
+
+
+def test_validate_bitbucket_trigger_unreachable(app):
+    @urlmatch(netloc=r"bitbucket.org")
+    def handler(url, request):
+        raise requests.exceptions.ConnectionError("connection refused")
+
+    with HTTMock(handler):
+        unvalidated_config = ValidatorContext(
+            {
+                "BITBUCKET_TRIGGER_CONFIG": {
+                    "CONSUMER_KEY": "foo",
+                    "CONSUMER_SECRET": "bar",
+                },
+            },
+            url_scheme_and_hostname=URLSchemeAndHostname("https", "quay.example.com"),
+        )
+
+        with pytest.raises(ConfigValidationException) as cve:
+            BitbucketTriggerValidator.validate(unvalidated_config)
+
+    assert str(cve.value) == "Could not connect to Bitbucket: connection refused"
@@ -1,4 +1,7 @@
+import json
+
 import pytest
+import requests
 
 from config import build_requests_session
 from httmock import urlmatch, HTTMock
@@ -55,9 +58,109 @@ def test_validate_elasticsearch(unvalidated_config, expected, app):
     def handler(url, request):
         return {"status_code": 200, "content": b"{}"}
 
-    with HTTMock(handler):
+    @urlmatch(netloc=r"", path=r"/_cluster/health")
+    def health_handler(url, request):
+        return {"status_code": 200, "content": json.dumps({"status": "green"})}
+
+    with HTTMock(handler, health_handler):
         if expected is not None:
             with pytest.raises(expected):
                 validator.validate(unvalidated_config)
         else:
             validator.validate(unvalidated_config)
+
+
+@pytest.mark.parametrize(
+    "health_response, error_message, warnings",
+    [
+        ((200, {"status": "green"}), None, []),
+        (
+            (200, {"status": "yellow"}),
+            None,
+            [
+                "Elasticsearch cluster at https://somehost:6666 has status yellow; replica shards "
+                + "are unassigned"
+            ],
+        ),
+        (
+            (200, {"status": "red"}),
+            "Elasticsearch cluster at https://somehost:6666 has status red; primary shards are "
+            + "unassigned",
+            None,
+        ),
+        (
+            (401, {}),
+            "Elasticsearch at https://somehost:6666 rejected the configured credentials with "
+            + "HTTP 401",
+            None,
+        ),
+        (
+            (500, {}),
+            "Unable to connect to Elasticsearch at https://somehost:6666: GET /_cluster/health "
+            + "returned HTTP 500",
+            None,
+        ),
+        (
+            requests.exceptions.ConnectionError("connection refused"),
+            "Could not connect to Elasticsearch at https://somehost:6666: connection refused",
+            None,
+        ),
+    ],
+)
+def test_validate_elasticsearch_cluster_health(health_response, error_message, warnings, app):
+    unvalidated_config = ValidatorContext(
+        {
+            "LOGS_MODEL": "elasticsearch",
+            "LOGS_MODEL_CONFIG": {
+                "elasticsearch_config": _TEST_ELASTICSEARCH_CONFIG,
+            },
+        }
+    )
+
+    @urlmatch(netloc=r"somehost:6666", path=r"/_cluster/health")
+    def health_handler(url, request):
+        assert request.headers["Authorization"].startswith("Basic ")
+        if isinstance(health_response, Exception):
+            raise health_response
+
+        (status_code, content) = health_response
+        return {"status_code": status_code, "content": json.dumps(content)}
+
+    @urlmatch(netloc=r"somehost:6666", path=r"/logentry_\*")
+    def index_handler(url, request):
+        return {"status_code": 200, "content": b"{}"}
+
+    with HTTMock(health_handler, index_handler):
+        if error_message is not None:
+            with pytest.raises(ConfigValidationException) as cve:
+                ElasticsearchValidator.validate(unvalidated_config)
+
+            assert str(cve.value) == error_message
+        else:
+            ElasticsearchValidator.validate(unvalidated_config)
+            assert unvalidated_config.warnings == warnings
+
+
+def test_validate_elasticsearch_aws_auth(app):
+    elasticsearch_config = dict(_TEST_ELASTICSEARCH_CONFIG)
+    elasticsearch_config["aws_region"] = "us-east-1"
+
+    unvalidated_config = ValidatorContext(
+        {
+            "LOGS_MODEL": "elasticsearch",
+            "LOGS_MODEL_CONFIG": {
+                "elasticsearch_config": elasticsearch_config,
+            },
+        }
+    )
+
+    @urlmatch(netloc=r"somehost:6666")
+    def handler(url, request):
+        assert request.headers["Authorization"].startswith("AWS4-HMAC-SHA256 ")
+        if url.path == "/_cluster/health":
+            return {"status_code": 200, "content": json.dumps({"status": "green"})}
+
+        return {"status_code": 200, "content": b"{}"}
+
+    with HTTMock(handler):
+        ElasticsearchValidator.validate(unvalidated_config)
@@ -0,0 +1,152 @@
+import smtplib
+import ssl
+
+import pytest
+
+from mock import patch
+
+from util.config.validator import ValidatorContext
+from util.config.validators import ConfigValidationException
+from util.config.validators.validate_email import EmailValidator
+
+from test.fixtures import *
+
+
+class FakeSMTP(object):
+    """
+    Stands in for smtplib.SMTP, with class attributes controlling how the fake server responds.
+    """
+
+    connect_error = None
+    starttls_error = None
+    login_error = None
+    auth_mechanisms = "PLAIN LOGIN"
+
+    def __init__(self, host, port, timeout=None):
+        if self.connect_error is not None:
+            raise self.connect_error
+
+        self.esmtp_features = {}
+        if self.auth_mechanisms is not None:
+            self.esmtp_features["auth"] = self.auth_mechanisms
+
+    def starttls(self):
+        if self.starttls_error is not None:
+            raise self.starttls_error
+
+    def ehlo_or_helo_if_needed(self):
+        pass
+
+    def has_extn(self, name):
+        return name.lower() in self.esmtp_features
+
+    def login(self, username, password):
+        if self.login_error is not None:
+            raise self.login_error
+
+    def close(self):
+        pass
+
+
+def _mail_config(**kwargs):
+    config = {
+        "FEATURE_MAILING": True,
+        "MAIL_SERVER": "mail.example.com",
+        "MAIL_USERNAME": "someuser",
+        "MAIL_PASSWORD": "somepassword",
+    }
+    config.update(kwargs)
+    return ValidatorContext(config)
+
+
+@pytest.mark.parametrize(
+    "unvalidated_config",
+    [
+        ({"FEATURE_MAILING": False}),
+        ({"FEATURE_MAILING": False, "MAIL_SERVER": "mail.example.com"}),
+    ],
+)
+def test_validate_noop(unvalidated_config, app):
+    EmailValidator.validate(ValidatorContext(unvalidated_config))
+
+
+def test_missing_server(app):
+    with pytest.raises(ConfigValidationException) as cve:
+        EmailValidator.validate(ValidatorContext({"FEATURE_MAILING": True}))
+
+    assert str(cve.value) == "Missing MAIL_SERVER"
+
+
+@pytest.mark.parametrize(
+    "fake_server, config, error_message",
+    [
+        ({}, {}, None),
+        ({}, {"MAIL_USERNAME": None}, None),
+        ({"auth_mechanisms": None}, {"MAIL_USERNAME": None}, None),
+        (
+            {"connect_error": ConnectionRefusedError(111, "Connection refused")},
+            {},
+            "Could not connect to SMTP server mail.example.com:587: [Errno 111] Connection refused",
+        ),
+        (
+            {"starttls_error": ssl.SSLError("wrong version number")},
+            {},
+            "TLS error with SMTP server mail.example.com:587: wrong version number",
+        ),
+        (
+            {"starttls_error": smtplib.SMTPNotSupportedError()},
+            {"MAIL_PORT": 25},
+            "SMTP server mail.example.com:25 does not support STARTTLS; disable MAIL_USE_TLS or "
+            + "use MAIL_USE_SSL",
+        ),
+        (
+            {"login_error": smtplib.SMTPAuthenticationError(535, b"5.7.8 Authentication failed")},
+            {},
+            "SMTP server mail.example.com:587 rejected the credentials for someuser: 535 5.7.8 "
+            + "Authentication failed",
+        ),
+        (
+            {"auth_mechanisms": None},
+            {},
+            "SMTP server mail.example.com:587 does not support authentication; remove "
+            + "MAIL_USERNAME",
+        ),
+        (
+            {"auth_mechanisms": "XOAUTH2"},
+            {},
+            "SMTP server mail.example.com:587 offers no supported AUTH mechanism (offered: "
+            + "XOAUTH2); expected one of CRAM-MD5, PLAIN, LOGIN",
+        ),
+    ],
+)
+def test_validate_email(fake_server, config, error_message, app):
+    fake_smtp = type("FakeSMTP", (FakeSMTP,), fake_server)
+    validator_context = _mail_config(**config)
+
+    with patch("smtplib.SMTP", fake_smtp):
+        if error_message is not None:
+            with pytest.raises(ConfigValidationException) as cve:
+                EmailValidator.validate(validator_context)
+
+            assert str(cve.value) == error_message
+        else:
+            EmailValidator.validate(validator_context)
+
+
+def test_validate_email_implicit_tls(app):
+    fake_smtp = type("FakeSMTP", (FakeSMTP,), {"starttls_error": AssertionError()})
+    validator_context = _mail_config(MAIL_USE_SSL=True, MAIL_PORT=465)
+
+    with patch("smtplib.SMTP_SSL", fake_smtp):
+        EmailValidator.validate(validator_context)
+
+    fake_smtp = type(
+        "FakeSMTP", (FakeSMTP,), {"connect_error": ssl.SSLError("certificate verify failed")}
+    )
+    with patch("smtplib.SMTP_SSL", fake_smtp):
+        with pytest.raises(ConfigValidationException) as cve:
+            EmailValidator.validate(validator_context)
+
+    assert str(cve.value) == (
+        "TLS error when connecting to SMTP server mail.example.com:465: certificate verify failed"
+    )
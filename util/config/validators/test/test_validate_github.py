@@ -3,6 +3,7 @@ import pytest
 from httmock import urlmatch, HTTMock
 
 from config import build_requests_session
+from util.config import URLSchemeAndHostname
 from util.config.validator import ValidatorContext
 from util.config.validators import ConfigValidationException
 from util.config.validators.validate_github import GitHubLoginValidator, GitHubTriggerValidator
@@ -23,6 +24,14 @@ def github_validator(request):
         ({"GITHUB_ENDPOINT": "http://github.com"}),
         ({"GITHUB_ENDPOINT": "http://github.com", "CLIENT_ID": "foo"}),
         ({"GITHUB_ENDPOINT": "http://github.com", "CLIENT_SECRET": "foo"}),
+        (
+            {
+                "GITHUB_ENDPOINT": "http://github.com",
+                "API_ENDPOINT": "github.com/api/v3",
+                "CLIENT_ID": "foo",
+                "CLIENT_SECRET": "foo",
+            }
+        ),
         (
             {
                 "GITHUB_ENDPOINT": "http://github.com",
@@ -78,3 +87,62 @@ def test_validate_github(github_validator, app):
 
     assert url_hit[0]
     assert url_hit[1]
+
+
+def test_validate_github_trigger_internal_hostname(github_validator, app):
+    @urlmatch(netloc=r"somehost")
+    def handler(url, request):
+        return {"status_code": 200, "content": "", "headers": {"X-GitHub-Request-Id": "foo"}}
+
+    @urlmatch(netloc=r"somehost", path=r"/api/v3/applications/foo/tokens/foo")
+    def app_handler(url, request):
+        return {"status_code": 404, "content": "", "headers": {"X-GitHub-Request-Id": "foo"}}
+
+    with HTTMock(app_handler, handler):
+        unvalidated_config = ValidatorContext(
+            {
+                github_validator.config_key: {
+                    "GITHUB_ENDPOINT": "http://somehost",
+                    "CLIENT_ID": "foo",
+                    "CLIENT_SECRET": "bar",
+                },
+            },
+            http_client=build_requests_session(),
+            url_scheme_and_hostname=URLSchemeAndHostname("http", "localhost:5000"),
+        )
+
+        github_validator.validate(unvalidated_config)
+
+    # Only triggers receive webhooks from GitHub.
+    assert bool(unvalidated_config.warnings) == github_validator.receives_webhooks
+
+
+def test_validate_github_enterprise_api_endpoint(github_validator, app):
+    url_hit = [False, False]
+
+    @urlmatch(netloc=r"someapihost", path=r"/")
+    def handler(url, request):
+        url_hit[0] = True
+        return {"status_code": 200, "content": "", "headers": {"X-GitHub-Request-Id": "foo"}}
+
+    @urlmatch(netloc=r"someapihost", path=r"/applications/foo/tokens/foo")
+    def app_handler(url, request):
+        url_hit[1] = True
+        return {"status_code": 404, "content": "", "headers": {"X-GitHub-Request-Id": "foo"}}
+
+    with HTTMock(app_handler, handler):
+        unvalidated_config = ValidatorContext(
+            {
+                github_validator.config_key: {
+                    "GITHUB_ENDPOINT": "http://somehost",
+                    "API_ENDPOINT": "http://someapihost/",
+                    "CLIENT_ID": "foo",
+                    "CLIENT_SECRET": "bar",
+                },
+            }
+        )
+
+        unvalidated_config.http_client = build_requests_session()
+        github_validator.validate(unvalidated_config)
+
+    assert url_hit == [True, True]
@@ -1,5 +1,6 @@
 import json
 import pytest
+import requests
 
 from httmock import urlmatch, HTTMock
 
@@ -63,3 +64,92 @@ def test_validate_gitlab_enterprise_trigger(app):
         validator.validate(unvalidated_config)
 
     assert url_hit[0]
+
+
+@pytest.mark.parametrize(
+    "server_hostname, expect_warning",
+    [
+        ("localhost:5000", True),
+        ("127.0.0.1", True),
+        ("10.0.0.5:8443", True),
+        ("[::1]:8443", True),
+        ("quay.example.com", False),
+        ("8.8.8.8", False),
+    ],
+)
+def test_validate_gitlab_trigger_internal_hostname(server_hostname, expect_warning, app):
+    @urlmatch(netloc=r"somegitlab", path="/oauth/token")
+    def handler(_, __):
+        return {"status_code": 400, "content": json.dumps({"error": "invalid code"})}
+
+    with HTTMock(handler):
+        unvalidated_config = ValidatorContext(
+            {
+                "GITLAB_TRIGGER_CONFIG": {
+                    "GITLAB_ENDPOINT": "http://somegitlab",
+                    "CLIENT_ID": "foo",
+                    "CLIENT_SECRET": "bar",
+                },
+            },
+            http_client=build_requests_session(),
+            url_scheme_and_hostname=URLSchemeAndHostname("https", server_hostname),
+        )
+
+        GitLabTriggerValidator.validate(unvalidated_config)
+
+    if expect_warning:
+        assert unvalidated_config.warnings == [
+            "Server hostname `%s` appears to be internal-only; GitLab will be unable to reach "
+            "Quay's webhooks" % server_hostname
+        ]
+    else:
+        assert not unvalidated_config.warnings
+
+
+def _raise(exception):
+    raise exception
+
+
+@pytest.mark.parametrize(
+    "response, error_message",
+    [
+        (
+            lambda: _raise(requests.exceptions.SSLError("certificate verify failed")),
+            "TLS error when connecting to GitLab at https://somegitlab: certificate verify failed",
+        ),
+        (
+            lambda: _raise(requests.exceptions.ConnectionError("connection refused")),
+            "Could not connect to GitLab at https://somegitlab: connection refused",
+        ),
+        (
+            lambda: {"status_code": 200, "content": "<html>not gitlab</html>"},
+            "https://somegitlab did not return a valid OAuth response; is it a GitLab endpoint?",
+        ),
+        (
+            lambda: {"status_code": 401, "content": json.dumps({"error": "invalid_client"})},
+            "Invalid client id or client secret",
+        ),
+    ],
+)
+def test_validate_gitlab_trigger_errors(response, error_message, app):
+    @urlmatch(netloc=r"somegitlab", path="/oauth/token")
+    def handler(_, __):
+        return response()
+
+    with HTTMock(handler):
+        unvalidated_config = ValidatorContext(
+            {
+                "GITLAB_TRIGGER_CONFIG": {
+                    "GITLAB_ENDPOINT": "https://somegitlab",
+                    "CLIENT_ID": "foo",
+                    "CLIENT_SECRET": "bar",
+                },
+            },
+            http_client=build_requests_session(),
+            url_scheme_and_hostname=URLSchemeAndHostname("https", "quay.example.com"),
+        )
+
+        with pytest.raises(ConfigValidationException) as cve:
+            GitLabTriggerValidator.validate(unvalidated_config)
+
+        assert str(cve.value) == error_message
@@ -1,4 +1,5 @@
 import pytest
+import requests
 
 from httmock import urlmatch, HTTMock
 
@@ -40,7 +41,7 @@ def test_validate_google_login(app):
         unvalidated_config = ValidatorContext(
             {
                 "GOOGLE_LOGIN_CONFIG": {
-                    "CLIENT_ID": "foo",
+                    "CLIENT_ID": "foo.apps.googleusercontent.com",
                     "CLIENT_SECRET": "bar",
                 },
             }
@@ -51,3 +52,52 @@ def test_validate_google_login(app):
         validator.validate(unvalidated_config)
 
     assert url_hit[0]
+
+
+@pytest.mark.parametrize(
+    "client_id",
+    [
+        "foo",
+        ".apps.googleusercontent.com",
+        "foo.apps.googleusercontent.com.example.com",
+    ],
+)
+def test_validate_google_login_malformed_client_id(client_id, app):
+    unvalidated_config = ValidatorContext(
+        {
+            "GOOGLE_LOGIN_CONFIG": {
+                "CLIENT_ID": client_id,
+                "CLIENT_SECRET": "bar",
+            },
+        }
+    )
+
+    with pytest.raises(ConfigValidationException) as cve:
+        GoogleLoginValidator.validate(unvalidated_config)
+
+    assert str(cve.value) == (
+        "Client ID `%s` is not a Google OAuth client ID; expected "
+        + "`<id>.apps.googleusercontent.com`"
+    ) % client_id
+
+
+def test_validate_google_login_unreachable(app):
+    @urlmatch(netloc=r"www.googleapis.com", path="/oauth2/v3/token")
+    def handler(_, __):
+        raise requests.exceptions.ConnectionError("connection refused")
+
+    with HTTMock(handler):
+        unvalidated_config = ValidatorContext(
+            {
+                "GOOGLE_LOGIN_CONFIG": {
+                    "CLIENT_ID": "foo.apps.googleusercontent.com",
+                    "CLIENT_SECRET": "bar",
+                },
+            }
+        )
+        unvalidated_config.http_client = build_requests_session()
+
+        with pytest.raises(ConfigValidationException) as cve:
+            GoogleLoginValidator.validate(unvalidated_config)
+
+    assert str(cve.value) == "Could not reach Google's OAuth endpoint: connection refused"
@@ -1,10 +1,18 @@
 import pytest
+import requests
+
+from cryptography.hazmat.backends import default_backend
+from cryptography.hazmat.primitives import serialization
+from cryptography.hazmat.primitives.asymmetric import ec, rsa
+from httmock import urlmatch, HTTMock
+from tempfile import NamedTemporaryFile
 
 from config import build_requests_session
 from util.config.validator import ValidatorContext
 from util.config.validators import ConfigValidationException
 from util.config.validators.validate_jwt import JWTAuthValidator
 from util.morecollections import AttrDict
+from util.security.test.test_ssl_util import generate_test_cert
 
 from test.test_external_jwt_authn import fake_jwt
 
@@ -74,3 +82,83 @@ def test_validated_jwt(username, password, expected_exception, app):
                 )
         else:
             JWTAuthValidator.validate(unvalidated_config, public_key_path=jwt_auth.public_key_path)
+
+
+def _public_key_pem(private_key):
+    return private_key.public_key().public_bytes(
+        encoding=serialization.Encoding.PEM,
+        format=serialization.PublicFormat.SubjectPublicKeyInfo,
+    )
+
+
+def _rsa_public_key():
+    return _public_key_pem(
+        rsa.generate_private_key(public_exponent=65537, key_size=2048, backend=default_backend())
+    )
+
+
+def _ec_public_key():
+    return _public_key_pem(ec.generate_private_key(ec.SECP256R1(), default_backend()))
+
+
+def _validate_jwt_key(public_key_contents):
+    with NamedTemporaryFile() as public_key_file:
+        public_key_file.write(public_key_contents)
+        public_key_file.flush()
+
+        config = {}
+        config["AUTHENTICATION_TYPE"] = "JWT"
+        config["JWT_AUTH_ISSUER"] = "someissuer"
+        config["JWT_VERIFY_ENDPOINT"] = "http://somejwt/verify"
+        config["JWT_GETUSER_ENDPOINT"] = "http://somejwt/getuser"
+
+        unvalidated_config = ValidatorContext(config)
+        unvalidated_config.config_provider = config_provider
+        unvalidated_config.http_client = build_requests_session()
+
+        JWTAuthValidator.validate(unvalidated_config, public_key_path=public_key_file.name)
+
+
+@pytest.mark.parametrize(
+    "public_key_contents, error_message",
+    [
+        (
+            lambda: generate_test_cert(hostname="somejwt")[0],
+            "JWT authentication public key file contains a certificate rather than a public key; "
+            + "extract the key with `openssl x509 -pubkey -noout`",
+        ),
+        (lambda: b"not a public key", "Could not load JWT authentication public key: "),
+        (
+            _ec_public_key,
+            "JWT authentication public key is not an RSA key; tokens are verified with RS256",
+        ),
+    ],
+)
+def test_invalid_public_key(public_key_contents, error_message, app):
+    with pytest.raises(ConfigValidationException) as cve:
+        _validate_jwt_key(public_key_contents())
+
+    assert str(cve.value).startswith(error_message)
+
+
+def test_unreachable_endpoint(app):
+    @urlmatch(netloc=r"somejwt")
+    def handler(_, __):
+        raise requests.exceptions.ConnectionError("connection refused")
+
+    with HTTMock(handler):
+        with pytest.raises(ConfigValidationException) as cve:
+            _validate_jwt_key(_rsa_public_key())
+
+    assert str(cve.value) == (
+        "Could not reach JWT authentication endpoint http://somejwt/verify: connection refused"
+    )
+
+
+def test_valid_public_key(app):
+    @urlmatch(netloc=r"somejwt")
+    def handler(_, __):
+        return {"status_code": 401, "content": ""}
+
+    with HTTMock(handler):
+        _validate_jwt_key(_rsa_public_key())
@@ -0,0 +1,123 @@
+import pytest
+
+from kafka.errors import KafkaTimeoutError, NoBrokersAvailable
+from mock import patch
+
+from util.config.validator import ValidatorContext
+from util.config.validators import ConfigValidationException
+from util.config.validators.validate_kafka import KafkaValidator
+
+from test.fixtures import *
+
+
+class FakeKafkaConsumer(object):
+    """
+    Stands in for kafka.KafkaConsumer, with class attributes controlling how the fake brokers
+    respond.
+    """
+
+    reachable = ["broker1:9092", "broker2:9092"]
+    topics_error = None
+
+    def __init__(self, bootstrap_servers=None, client_id=None, api_version_auto_timeout_ms=None):
+        if not set(bootstrap_servers) & set(self.reachable):
+            raise NoBrokersAvailable()
+
+    def topics(self):
+        if self.topics_error is not None:
+            raise self.topics_error
+
+        return {"logentry", "othertopic"}
+
+    def close(self):
+        pass
+
+
+def _kafka_config(**kwargs):
+    kafka_config = {
+        "bootstrap_servers": ["broker1:9092", "broker2:9092"],
+        "topic": "logentry",
+    }
+    kafka_config.update(kwargs)
+    return ValidatorContext(
+        {
+            "LOGS_MODEL": "elasticsearch",
+            "LOGS_MODEL_CONFIG": {"producer": "kafka", "kafka_config": kafka_config},
+        }
+    )
+
+
+@pytest.mark.parametrize(
+    "unvalidated_config, expected_message",
+    [
+        ({}, "LOGS_MODEL not set to Elasticsearch"),
+        (
+            {"LOGS_MODEL": "elasticsearch", "LOGS_MODEL_CONFIG": {"producer": "kinesis_stream"}},
+            "Producer not set to 'kafka'",
+        ),
+        (
+            {"LOGS_MODEL": "elasticsearch", "LOGS_MODEL_CONFIG": {"producer": "kafka"}},
+            "No kafka_config defined",
+        ),
+        (
+            {
+                "LOGS_MODEL": "elasticsearch",
+                "LOGS_MODEL_CONFIG": {"producer": "kafka", "kafka_config": {"topic": "logentry"}},
+            },
+            "Missing bootstrap_servers in kafka_config",
+        ),
+    ],
+)
+def test_validate_kafka_config(unvalidated_config, expected_message, app):
+    with pytest.raises(ConfigValidationException) as cve:
+        KafkaValidator.validate(ValidatorContext(unvalidated_config))
+
+    assert str(cve.value) == expected_message
+
+
+@pytest.mark.parametrize(
+    "fake_brokers, config, expected_message, expected_warnings",
+    [
+        ({}, {}, None, []),
+        ({}, {"bootstrap_servers": "broker1:9092"}, None, []),
+        (
+            {"reachable": ["broker2:9092"]},
+            {},
+            None,
+            ["Some Kafka brokers are unreachable: broker1:9092"],
+        ),
+        (
+            {"reachable": []},
+            {},
+            "No Kafka brokers reachable; tried broker1:9092, broker2:9092",
+            [],
+        ),
+        (
+            {},
+            {"topic": "missingtopic"},
+            "Kafka topic `missingtopic` not found on the configured brokers",
+            [],
+        ),
+        (
+            {"topics_error": KafkaTimeoutError("Failed to update metadata")},
+            {},
+            "Could not list topics from Kafka broker broker1:9092: KafkaTimeoutError: Failed to "
+            + "update metadata",
+            [],
+        ),
+    ],
+)
+def test_validate_kafka(fake_brokers, config, expected_message, expected_warnings, app):
+    fake_consumer = type("FakeKafkaConsumer", (FakeKafkaConsumer,), fake_brokers)
+    validator_context = _kafka_config(**config)
+
+    with patch("util.config.validators.validate_kafka.KafkaConsumer", fake_consumer):
+        if expected_message is not None:
+            with pytest.raises(ConfigValidationException) as cve:
+                KafkaValidator.validate(validator_context)
+
+            assert str(cve.value) == expected_message
+        else:
+            KafkaValidator.validate(validator_context)
+
+    assert validator_context.warnings == expected_warnings
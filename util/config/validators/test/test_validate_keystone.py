@@ -74,3 +74,60 @@ def test_validated_keystone(admin_tenant_id, expected_exception, app):
                 KeystoneValidator.validate(unvalidated_config)
         else:
             KeystoneValidator.validate(unvalidated_config)
+
+
+@pytest.mark.parametrize("auth_version", [1, "4", "foo"])
+def test_unsupported_auth_version(auth_version, app):
+    config = {}
+    config["AUTHENTICATION_TYPE"] = "Keystone"
+    config["KEYSTONE_AUTH_URL"] = "http://localhost/v3"
+    config["KEYSTONE_AUTH_VERSION"] = auth_version
+    config["KEYSTONE_ADMIN_USERNAME"] = "adminuser"
+    config["KEYSTONE_ADMIN_PASSWORD"] = "adminpass"
+    config["KEYSTONE_ADMIN_TENANT"] = "somegroupid"
+
+    with pytest.raises(ConfigValidationException) as cve:
+        KeystoneValidator.validate(ValidatorContext(config))
+
+    assert str(cve.value) == (
+        "Unsupported Keystone auth version `%s`; expected 2 or 3" % auth_version
+    )
+
+
+@pytest.mark.parametrize("auth_version", [2, 3])
+def test_invalid_admin_credentials(auth_version, app):
+    with fake_keystone(auth_version) as keystone_auth:
+        auth_url = keystone_auth.auth_url
+
+        config = {}
+        config["AUTHENTICATION_TYPE"] = "Keystone"
+        config["KEYSTONE_AUTH_URL"] = auth_url
+        config["KEYSTONE_AUTH_VERSION"] = auth_version
+        config["KEYSTONE_ADMIN_USERNAME"] = "adminuser"
+        config["KEYSTONE_ADMIN_PASSWORD"] = "wrongpass"
+        config["KEYSTONE_ADMIN_TENANT"] = "somegroupid"
+
+        with pytest.raises(ConfigValidationException) as cve:
+            KeystoneValidator.validate(ValidatorContext(config))
+
+        assert str(cve.value).startswith(
+            "Could not authenticate to Keystone v%s at %s: " % (auth_version, auth_url)
+        )
+
+
+def test_unreachable_keystone(app):
+    config = {}
+    config["AUTHENTICATION_TYPE"] = "Keystone"
+    config["KEYSTONE_AUTH_URL"] = "http://localhost:1/v3"
+    config["KEYSTONE_AUTH_VERSION"] = 3
+    config["KEYSTONE_ADMIN_USERNAME"] = "adminuser"
+    config["KEYSTONE_ADMIN_PASSWORD"] = "adminpass"
+    config["KEYSTONE_ADMIN_TENANT"] = "somegroupid"
+
+    with pytest.raises(ConfigValidationException) as cve:
+        KeystoneValidator.validate(ValidatorContext(config))
+
+    assert str(cve.value).startswith(
+        "Could not authenticate to Keystone v3 at http://localhost:1/v3: "
+        + "Could not connect to Keystone: "
+    )
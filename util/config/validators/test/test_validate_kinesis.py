@@ -1,6 +1,7 @@
 import pytest
 import boto3
 
+from botocore.exceptions import ClientError, EndpointConnectionError
 from datetime import datetime
 from mock import patch
 
@@ -94,3 +95,51 @@ def test_validate_elasticsearch(unvalidated_config, expected):
                 validator.validate(unvalidated_config)
         else:
             validator.validate(unvalidated_config)
+
+
+@pytest.mark.parametrize(
+    "error, expected_message",
+    [
+        (
+            ClientError(
+                {"Error": {"Code": "ResourceNotFoundException", "Message": "not found"}},
+                "DescribeStream",
+            ),
+            "Kinesis stream `somestream` not found in region some-region-1",
+        ),
+        (
+            ClientError(
+                {"Error": {"Code": "UnrecognizedClientException", "Message": "bad token"}},
+                "DescribeStream",
+            ),
+            "Unable to describe Kinesis stream `somestream`: An error occurred "
+            + "(UnrecognizedClientException) when calling the DescribeStream operation: bad token",
+        ),
+        (
+            EndpointConnectionError(endpoint_url="https://kinesis.some-region-1.amazonaws.com/"),
+            "Could not connect to Kinesis in region some-region-1: Could not connect to the "
+            + 'endpoint URL: "https://kinesis.some-region-1.amazonaws.com/"',
+        ),
+    ],
+)
+def test_validate_kinesis_errors(error, expected_message):
+    unvalidated_config = ValidatorContext(
+        {
+            "LOGS_MODEL": "elasticsearch",
+            "LOGS_MODEL_CONFIG": {
+                "producer": "kinesis_stream",
+                "kinesis_stream_config": {
+                    "stream_name": "somestream",
+                    "aws_region": "some-region-1",
+                },
+            },
+        }
+    )
+
+    with patch("boto3.client") as client:
+        client.return_value.describe_stream.side_effect = error
+
+        with pytest.raises(ConfigValidationException) as cve:
+            KinesisValidator.validate(unvalidated_config)
+
+    assert str(cve.value) == expected_message
@@ -94,3 +94,33 @@ def test_validated_ldap(admin_dn, admin_passwd, user_rdn, expected_exception, ap
     else:
         with mock_ldap():
             LDAPValidator.validate(unvalidated_config)
+
+
+@pytest.mark.parametrize(
+    "uri, expected_warnings",
+    [
+        (
+            "ldap://localhost",
+            [
+                "LDAP URI ldap://localhost does not use TLS, so the admin bind password is sent in "
+                + "plaintext. Consider using ldaps:// instead."
+            ],
+        ),
+        ("ldaps://localhost", []),
+    ],
+)
+def test_insecure_uri_warning(uri, expected_warnings, app):
+    config = {}
+    config["AUTHENTICATION_TYPE"] = "LDAP"
+    config["LDAP_BASE_DN"] = ["dc=quay", "dc=io"]
+    config["LDAP_ADMIN_DN"] = "uid=testy,ou=employees,dc=quay,dc=io"
+    config["LDAP_ADMIN_PASSWD"] = "password"
+    config["LDAP_USER_RDN"] = ["ou=employees"]
+    config["LDAP_URI"] = uri
+
+    unvalidated_config = ValidatorContext(config, config_provider=config_provider)
+
+    with mock_ldap():
+        LDAPValidator.validate(unvalidated_config)
+
+    assert unvalidated_config.warnings == expected_warnings
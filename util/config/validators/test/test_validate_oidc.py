@@ -35,11 +35,17 @@ def test_validate_oidc_login(app):
     def handler(_, __):
         url_hit[0] = True
         data = {
-            "token_endpoint": "foobar",
+            "authorization_endpoint": "http://someserver/auth",
+            "token_endpoint": "http://someserver/token",
+            "jwks_uri": "http://someserver/jwks",
         }
         return {"status_code": 200, "content": json.dumps(data)}
 
-    with HTTMock(handler):
+    @urlmatch(netloc=r"someserver", path=r"/token", method="POST")
+    def token_handler(_, __):
+        return {"status_code": 200, "content": json.dumps({"access_token": "sometoken"})}
+
+    with HTTMock(handler, token_handler):
         validator = OIDCLoginValidator()
         unvalidated_config = ValidatorContext(
             {
@@ -56,3 +62,124 @@ def test_validate_oidc_login(app):
         validator.validate(unvalidated_config)
 
     assert url_hit[0]
+
+
+@pytest.mark.parametrize(
+    "status_code, content, error_message",
+    [
+        (
+            404,
+            "",
+            "Could not validate OIDC service something: OIDC discovery information not found at "
+            + "http://someserver/.well-known/openid-configuration; check that OIDC_SERVER is the "
+            + "issuer",
+        ),
+        (
+            200,
+            "not json",
+            "Could not validate OIDC service something: Could not parse OIDC discovery information",
+        ),
+        (
+            200,
+            json.dumps({"token_endpoint": "http://someserver/token"}),
+            "Could not validate OIDC service something: OIDC discovery information is missing "
+            + "authorization_endpoint, jwks_uri",
+        ),
+        (
+            200,
+            json.dumps(
+                {
+                    "authorization_endpoint": "http://someserver/auth",
+                    "token_endpoint": "/token",
+                    "jwks_uri": "http://someserver/jwks",
+                }
+            ),
+            "Could not validate OIDC service something: OIDC discovery information has an invalid "
+            + "token_endpoint: `/token`",
+        ),
+    ],
+)
+def test_validate_invalid_oidc_discovery(status_code, content, error_message, app):
+    @urlmatch(netloc=r"someserver", path=r"/\.well-known/openid-configuration")
+    def handler(_, __):
+        return {"status_code": status_code, "content": content}
+
+    with HTTMock(handler):
+        validator = OIDCLoginValidator()
+        unvalidated_config = ValidatorContext(
+            {
+                "SOMETHING_LOGIN_CONFIG": {
+                    "CLIENT_ID": "foo",
+                    "CLIENT_SECRET": "bar",
+                    "OIDC_SERVER": "http://someserver",
+                    "DEBUGGING": True,  # Allows for HTTP.
+                },
+            }
+        )
+        unvalidated_config.http_client = build_requests_session()
+
+        with pytest.raises(ConfigValidationException) as cve:
+            validator.validate(unvalidated_config)
+
+        assert str(cve.value) == error_message
+
+
+@pytest.mark.parametrize(
+    "status_code, content, error_message, warnings",
+    [
+        (200, {"access_token": "sometoken"}, None, []),
+        (
+            400,
+            {"error": "unsupported_grant_type"},
+            None,
+            [
+                "Could not verify the client secret of OIDC service something: the token endpoint "
+                + "returned `unsupported_grant_type` for a client credentials grant"
+            ],
+        ),
+        (
+            401,
+            {"error": "invalid_client"},
+            "Invalid client id or client secret for OIDC service something",
+            None,
+        ),
+        (401, None, "Invalid client id or client secret for OIDC service something", None),
+    ],
+)
+def test_validate_oidc_client_credentials(status_code, content, error_message, warnings, app):
+    @urlmatch(netloc=r"someserver", path=r"/\.well-known/openid-configuration")
+    def discovery_handler(_, __):
+        data = {
+            "authorization_endpoint": "http://someserver/auth",
+            "token_endpoint": "http://someserver/token",
+            "jwks_uri": "http://someserver/jwks",
+        }
+        return {"status_code": 200, "content": json.dumps(data)}
+
+    @urlmatch(netloc=r"someserver", path=r"/token", method="POST")
+    def token_handler(_, request):
+        assert "grant_type=client_credentials" in request.body
+        return {"status_code": status_code, "content": json.dumps(content) if content else ""}
+
+    with HTTMock(discovery_handler, token_handler):
+        validator = OIDCLoginValidator()
+        unvalidated_config = ValidatorContext(
+            {
+                "SOMETHING_LOGIN_CONFIG": {
+                    "CLIENT_ID": "foo",
+                    "CLIENT_SECRET": "bar",
+                    "OIDC_SERVER": "http://someserver",
+                    "DEBUGGING": True,  # Allows for HTTP.
+                },
+            }
+        )
+        unvalidated_config.http_client = build_requests_session()
+
+        if error_message is not None:
+            with pytest.raises(ConfigValidationException) as cve:
+                validator.validate(unvalidated_config)
+
+            assert str(cve.value) == error_message
+        else:
+            validator.validate(unvalidated_config)
+            assert unvalidated_config.warnings == warnings
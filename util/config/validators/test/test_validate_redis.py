@@ -18,7 +18,7 @@ from util.morecollections import AttrDict
     [
         ({}, None, None, False, ConfigValidationException),
         ({"BUILDLOGS_REDIS": {}}, None, None, False, ConfigValidationException),
-        ({"BUILDLOGS_REDIS": {"host": "somehost"}}, None, None, False, redis.ConnectionError),
+        ({"BUILDLOGS_REDIS": {"host": "somehost"}}, None, None, False, ConfigValidationException),
         ({"BUILDLOGS_REDIS": {"host": "localhost"}}, None, None, True, None),
     ],
 )
@@ -35,3 +35,20 @@ def test_validate_redis(unvalidated_config, user, user_password, use_mock, expec
                 validator.validate(unvalidated_config)
         else:
             validator.validate(unvalidated_config)
+
+
+def test_validate_redis_error_hides_password(app):
+    redis_config = {"host": "somehost", "password": "hunter2"}
+    error = redis.AuthenticationError(
+        "invalid password=hunter2 for redis://:hunter2@somehost:6379/0 (hunter2)"
+    )
+
+    with patch("redis.StrictRedis.ping", side_effect=error):
+        with pytest.raises(ConfigValidationException) as ipe:
+            RedisValidator.validate(ValidatorContext({"BUILDLOGS_REDIS": redis_config}))
+
+    assert "hunter2" not in str(ipe.value)
+    assert str(ipe.value) == (
+        "Could not connect to redis: invalid password=******** for "
+        + "redis://********@somehost:6379/0 (********)"
+    )
@@ -0,0 +1,96 @@
+import pytest
+
+from util.config.validator import ValidatorContext
+from util.config.validators import ConfigValidationException
+from util.config.validators.validate_secret_keys import SecretKeyValidator
+
+from test.fixtures import *
+
+REMEDIATION = "Generate a new key with `openssl rand -hex 40`"
+DATABASE_REMEDIATION = (
+    REMEDIATION + "; note that existing encrypted fields cannot be decrypted after changing it"
+)
+
+
+@pytest.mark.parametrize(
+    "unvalidated_config",
+    [
+        ({}),
+        ({"SECRET_KEY": None}),
+        (
+            {
+                "SECRET_KEY": "7316125364452178963051849536253498113960376862845226947451"
+                + "6931372590871244"
+            }
+        ),
+        ({"SECRET_KEY": "9f4d7a31c0e25b86fa14d3c92e7b60a8d51f3e9c2b74a06d8e15f93c7a2b4d60"}),
+        ({"SECRET_KEY": "c4b1f6a2-93d7-4e58-8a0b-2f61d9e7c350"}),
+        (
+            {
+                "DATABASE_SECRET_KEY": "9f4d7a31c0e25b86fa14d3c92e7b60a8d51f3e9c2b74a06d8e15f93c"
+                + "7a2b4d60"
+            }
+        ),
+    ],
+)
+def test_validate_secret_keys(unvalidated_config, app):
+    validator_context = ValidatorContext(unvalidated_config)
+    SecretKeyValidator.validate(validator_context)
+    assert validator_context.warnings == []
+
+
+def test_medium_strength_secret_key(app):
+    validator_context = ValidatorContext(
+        {"DATABASE_SECRET_KEY": "abcdabcdbadcdcbaacbdbdcaabcdcdabbacddcab"}
+    )
+    SecretKeyValidator.validate(validator_context)
+
+    assert validator_context.warnings == [
+        "DATABASE_SECRET_KEY has an estimated entropy of 80 bits; at least 128 are recommended. "
+        + DATABASE_REMEDIATION
+    ]
+
+
+@pytest.mark.parametrize(
+    "secret_key, expected_message",
+    [
+        ("", "SECRET_KEY is a well-known placeholder value. %s" % REMEDIATION),
+        ("ChangeMe", "SECRET_KEY is a well-known placeholder value. %s" % REMEDIATION),
+        ("ab" * 30, "SECRET_KEY is a repeating sequence of characters. %s" % REMEDIATION),
+        (
+            "9f4d7a31c0e25b86fa14d3c92e7b60a8",
+            "SECRET_KEY is 32 characters long; at least 40 are required. %s" % REMEDIATION,
+        ),
+        (
+            "a" * 39 + "b",
+            "SECRET_KEY has an estimated entropy of 6 bits; at least 64 are required. %s"
+            % REMEDIATION,
+        ),
+        (
+            "abab" * 9 + "aabb",
+            "SECRET_KEY has an estimated entropy of 40 bits; at least 64 are required. %s"
+            % REMEDIATION,
+        ),
+    ],
+)
+def test_invalid_secret_keys(secret_key, expected_message, app):
+    with pytest.raises(ConfigValidationException) as cve:
+        SecretKeyValidator.validate(ValidatorContext({"SECRET_KEY": secret_key}))
+
+    assert str(cve.value) == expected_message
+
+
+def test_invalid_database_secret_key(app):
+    validator_context = ValidatorContext(
+        {
+            "SECRET_KEY": "9f4d7a31c0e25b86fa14d3c92e7b60a8d51f3e9c2b74a06d8e15f93c7a2b4d60",
+            "DATABASE_SECRET_KEY": "changeme",
+        }
+    )
+
+    with pytest.raises(ConfigValidationException) as cve:
+        SecretKeyValidator.validate(validator_context)
+
+    assert str(cve.value) == (
+        "DATABASE_SECRET_KEY is a well-known placeholder value. %s" % DATABASE_REMEDIATION
+    )
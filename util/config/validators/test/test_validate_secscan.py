@@ -1,8 +1,15 @@
+import base64
+import json
+
 import pytest
+import requests
+
+from httmock import urlmatch, HTTMock
 
 from config import build_requests_session
 from util.config import URLSchemeAndHostname
 from util.config.validator import ValidatorContext
+from util.config.validators import ConfigValidationException
 from util.config.validators.validate_secscan import SecurityScannerValidator
 from util.secscan.fake import fake_security_scanner
 
@@ -66,3 +73,94 @@ def test_validate(unvalidated_config, expected_error, app):
                 SecurityScannerValidator.validate(unvalidated_config)
         else:
             SecurityScannerValidator.validate(unvalidated_config)
+
+
+VALID_PSK = base64.b64encode(b"x" * 32).decode("ascii")
+
+
+@pytest.mark.parametrize(
+    "psk, response, error_message, warnings",
+    [
+        (None, (200, {"state": "somestate"}), None, []),
+        (VALID_PSK, (200, {"state": "somestate"}), None, []),
+        (
+            base64.b64encode(b"short").decode("ascii"),
+            (200, {"state": "somestate"}),
+            None,
+            ["SECURITY_SCANNER_V4_PSK decodes to 5 bytes; at least 32 bytes are recommended"],
+        ),
+        (
+            VALID_PSK + "\n",
+            None,
+            "SECURITY_SCANNER_V4_PSK contains whitespace; remove any spaces or newlines",
+            None,
+        ),
+        (
+            "not!base64",
+            None,
+            "SECURITY_SCANNER_V4_PSK is not valid base64: ",
+            None,
+        ),
+        (
+            base64.urlsafe_b64encode(b"\xfb\xff" * 16).decode("ascii"),
+            None,
+            "SECURITY_SCANNER_V4_PSK is URL-safe base64; replace `-` with `+` and `_` with `/`",
+            None,
+        ),
+        (
+            VALID_PSK,
+            (401, {}),
+            "V4 security scanner at http://fakeclair rejected the request with HTTP 401; check "
+            + "that SECURITY_SCANNER_V4_PSK matches the PSK configured in Clair",
+            None,
+        ),
+        (
+            VALID_PSK,
+            (500, {}),
+            "V4 security scanner at http://fakeclair responded to the index state request with "
+            + "HTTP 500",
+            None,
+        ),
+        (
+            VALID_PSK,
+            requests.exceptions.ConnectionError("connection refused"),
+            "Could not validate V4 security scanner at http://fakeclair: Connection error when "
+            + "trying to connect to security scanner endpoint: connection refused",
+            None,
+        ),
+    ],
+)
+def test_validate_v4(psk, response, error_message, warnings, app):
+    @urlmatch(netloc=r"fakeclair", path=r"/indexer/api/v1/index_state")
+    def index_state_handler(_, __):
+        if isinstance(response, Exception):
+            raise response
+
+        (status_code, content) = response
+        return {"status_code": status_code, "content": json.dumps(content)}
+
+    config = {
+        "FEATURE_SECURITY_SCANNER": True,
+        "SECURITY_SCANNER_V4_ENDPOINT": "http://fakeclair",
+    }
+    if psk is not None:
+        config["SECURITY_SCANNER_V4_PSK"] = psk
+
+    unvalidated_config = ValidatorContext(
+        config,
+        feature_sec_scanner=True,
+        is_testing=True,
+        http_client=build_requests_session(),
+        url_scheme_and_hostname=URLSchemeAndHostname("http", "localhost:5000"),
+    )
+
+    # Only the V4 security scanner is configured, so no V2 endpoint is contacted.
+    with HTTMock(index_state_handler):
+        if error_message is not None:
+            with pytest.raises(ConfigValidationException) as cve:
+                SecurityScannerValidator.validate(unvalidated_config)
+
+            assert str(cve.value).startswith(error_message)
+        else:
+            SecurityScannerValidator.validate(unvalidated_config)
+            assert unvalidated_config.warnings == warnings
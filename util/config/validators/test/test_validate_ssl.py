@@ -1,11 +1,17 @@
+import codecs
+import os
+
 import pytest
 
+from cryptography.hazmat.primitives.asymmetric import ec
+from io import BytesIO
 from mock import patch
 from tempfile import NamedTemporaryFile
 
 from util.config.validator import ValidatorContext
 from util.config.validators import ConfigValidationException
-from util.config.validators.validate_ssl import SSLValidator, SSL_FILENAMES
+from util.config.validators.validate_ssl import SSLValidator, SSL_FILENAMES, EXTRA_CA_DIRECTORY
+from util.security.ssl import load_certificates
 from util.security.test.test_ssl_util import generate_test_cert
 from util.bytes import Bytes
 
@@ -13,17 +19,72 @@ from test.fixtures import *
 from app import config_provider
 
 
+MISSING_SSL_FILES = (
+    "Missing required SSL file(s): ssl.cert, ssl.key. Provide them, or set "
+    + "EXTERNAL_TLS_TERMINATION if TLS is terminated before Quay"
+)
+
+PLAIN_HTTP_WARNING = (
+    "PREFERRED_URL_SCHEME is http, so registry credentials and content are sent unencrypted. "
+    + "Use https outside of test setups"
+)
+
+
 @pytest.mark.parametrize(
-    "unvalidated_config",
+    "unvalidated_config, ssl_files_exist, warnings",
     [
-        ({}),
-        ({"PREFERRED_URL_SCHEME": "http"}),
-        ({"PREFERRED_URL_SCHEME": "https", "EXTERNAL_TLS_TERMINATION": True}),
+        ({}, False, [PLAIN_HTTP_WARNING]),
+        ({"PREFERRED_URL_SCHEME": "http"}, False, [PLAIN_HTTP_WARNING]),
+        (
+            {"PREFERRED_URL_SCHEME": "http"},
+            True,
+            [
+                "SSL certificate files are present, but PREFERRED_URL_SCHEME is http, so Quay "
+                + "generates http:// URLs. Set PREFERRED_URL_SCHEME to https"
+            ],
+        ),
+        (
+            {"PREFERRED_URL_SCHEME": "http", "EXTERNAL_TLS_TERMINATION": True},
+            False,
+            [
+                "EXTERNAL_TLS_TERMINATION is set, but PREFERRED_URL_SCHEME is http, so Quay "
+                + "generates http:// URLs. Set PREFERRED_URL_SCHEME to https"
+            ],
+        ),
+        ({"PREFERRED_URL_SCHEME": "https", "EXTERNAL_TLS_TERMINATION": True}, False, []),
+        ({"PREFERRED_URL_SCHEME": "https", "EXTERNAL_TLS_TERMINATION": True}, True, []),
     ],
 )
-def test_skip_validate_ssl(unvalidated_config, app):
-    validator = SSLValidator()
-    validator.validate(ValidatorContext(unvalidated_config))
+def test_skip_validate_ssl(unvalidated_config, ssl_files_exist, warnings, app):
+    validator_context = ValidatorContext(unvalidated_config, config_provider=config_provider)
+
+    with patch("app.config_provider.volume_file_exists", return_value=ssl_files_exist):
+        SSLValidator.validate(validator_context)
+
+    assert validator_context.warnings == warnings
+
+
+def test_validate_ssl_missing_files(app):
+    validator_context = ValidatorContext(
+        {"PREFERRED_URL_SCHEME": "https", "SERVER_HOSTNAME": "someserver"},
+        config_provider=config_provider,
+    )
+
+    with patch("app.config_provider.volume_file_exists", return_value=False):
+        with pytest.raises(ConfigValidationException) as ipe:
+            SSLValidator.validate(validator_context)
+
+    assert str(ipe.value) == MISSING_SSL_FILES
+
+    # Only the missing file is reported.
+    with patch("app.config_provider.volume_file_exists", side_effect=lambda f: f == "ssl.cert"):
+        with pytest.raises(ConfigValidationException) as ipe:
+            SSLValidator.validate(validator_context)
+
+    assert str(ipe.value) == (
+        "Missing required SSL file(s): ssl.key. Provide them, or set EXTERNAL_TLS_TERMINATION if "
+        + "TLS is terminated before Quay"
+    )
 
 
 @pytest.mark.parametrize(
@@ -57,6 +118,63 @@ def test_skip_validate_ssl(unvalidated_config, app):
         ),
         (generate_test_cert(hostname="someserver:more"), "someserver:more", None, None),
         (generate_test_cert(hostname="someserver:more"), "someserver:more:1234", None, None),
+        (
+            generate_test_cert(hostname="someserver", san_list=[b"IP:10.0.0.1"]),
+            "10.0.0.1",
+            None,
+            None,
+        ),
+        (
+            generate_test_cert(hostname="someserver", san_list=[b"IP:10.0.0.1"]),
+            "10.0.0.1:8443",
+            None,
+            None,
+        ),
+        (
+            generate_test_cert(hostname="someserver", san_list=[b"IP:2001:db8::1"]),
+            "[2001:db8::1]:8443",
+            None,
+            None,
+        ),
+        (
+            generate_test_cert(hostname="someserver", san_list=[b"IP:10.0.0.1"]),
+            "10.0.0.2",
+            ConfigValidationException,
+            'Supported names "someserver, 10.0.0.1" in SSL cert do not match server hostname '
+            + '"10.0.0.2"',
+        ),
+        (
+            generate_test_cert(hostname="otherserver", san_list=[b"DNS:b.example.com"]),
+            "someserver",
+            ConfigValidationException,
+            'Supported names "b.example.com, otherserver" in SSL cert do not match server '
+            + 'hostname "someserver"',
+        ),
+        (
+            generate_test_cert(
+                hostname="otherserver",
+                san_list=[("DNS:name%02d.example.com" % i).encode("ascii") for i in range(12)],
+            ),
+            "someserver",
+            ConfigValidationException,
+            'Supported names "name00.example.com, name01.example.com, name02.example.com, '
+            + "name03.example.com, name04.example.com, name05.example.com, name06.example.com, "
+            + 'name07.example.com, name08.example.com, name09.example.com and 3 more" in SSL cert '
+            + 'do not match server hostname "someserver"',
+        ),
+        (
+            generate_test_cert(hostname="otherserver", san_list=[b"DNS:*.example.com"]),
+            "a.example.com",
+            None,
+            None,
+        ),
+        (
+            generate_test_cert(hostname="otherserver", san_list=[b"DNS:*.example.com"]),
+            "a.b.example.com",
+            ConfigValidationException,
+            'Wildcard name "*.example.com" in SSL cert only covers a single level of subdomain, '
+            + 'so it does not match server hostname "a.b.example.com"',
+        ),
     ],
 )
 def test_validate_ssl(cert, server_hostname, expected_error, error_message, app):
@@ -98,3 +216,357 @@ def test_validate_ssl(cert, server_hostname, expected_error, error_message, app)
                     assert str(ipe.value) == error_message
                 else:
                     validator.validate(config)
+
+
+def _validate_ssl_files(
+    cert_contents, key_contents, server_hostname="someserver", extra_ca_certs=None
+):
+    with NamedTemporaryFile(delete=False) as cert_file:
+        cert_file.write(cert_contents)
+
+    with NamedTemporaryFile(delete=False) as key_file:
+        key_file.write(key_contents)
+
+    extra_ca_certs = extra_ca_certs or {}
+
+    def get_volume_file(filename, mode="r"):
+        if filename == SSL_FILENAMES[0]:
+            return open(cert_file.name, mode=mode)
+
+        if filename == SSL_FILENAMES[1]:
+            return open(key_file.name, mode=mode)
+
+        for extra_ca_filename, extra_ca_contents in extra_ca_certs.items():
+            if filename == os.path.join(EXTRA_CA_DIRECTORY, extra_ca_filename):
+                return BytesIO(extra_ca_contents)
+
+        return None
+
+    config = ValidatorContext(
+        {
+            "PREFERRED_URL_SCHEME": "https",
+            "SERVER_HOSTNAME": server_hostname,
+        }
+    )
+    config.config_provider = config_provider
+
+    with patch("app.config_provider.volume_file_exists", return_value=True):
+        with patch("app.config_provider.get_volume_file", get_volume_file):
+            with patch(
+                "app.config_provider.list_volume_directory", return_value=list(extra_ca_certs)
+            ):
+                SSLValidator.validate(config)
+
+    return config
+
+
+def test_validate_ssl_chain(app):
+    (cert, key) = generate_test_cert(hostname="someserver")
+    (intermediate, _) = generate_test_cert(hostname="someintermediate")
+
+    _validate_ssl_files(cert + intermediate, key)
+
+
+def test_validate_ssl_system_trust(app):
+    root = generate_test_cert(hostname="someroot", is_ca=True)
+    (cert, key) = generate_test_cert(
+        hostname="someserver", expires=60 * 60 * 24 * 90, issuer=root, key_size=4096
+    )
+
+    # Self-signed.
+    (self_signed_cert, self_signed_key) = generate_test_cert(
+        hostname="someserver", expires=60 * 60 * 24 * 90, key_size=4096
+    )
+    warnings = _validate_ssl_files(self_signed_cert, self_signed_key).warnings
+    assert len(warnings) == 1
+    assert warnings[0].startswith(
+        "SSL certificate (subject /CN=someserver, issued by /CN=someserver) could not be "
+        + "verified against the system trust store: self"
+    )
+
+    # Signed by an untrusted root.
+    assert len(_validate_ssl_files(cert, key).warnings) == 1
+
+    # Signed by a trusted root.
+    trusted = load_certificates(root[0])
+    with patch(
+        "util.config.validators.validate_ssl._system_trusted_certificates", return_value=trusted
+    ):
+        assert not _validate_ssl_files(cert, key).warnings
+
+
+def test_validate_ssl_extra_ca_trust(app):
+    root = generate_test_cert(hostname="someroot", is_ca=True)
+    other_root = generate_test_cert(hostname="someotherroot", is_ca=True)
+    (cert, key) = generate_test_cert(
+        hostname="someserver", expires=60 * 60 * 24 * 90, issuer=root, key_size=4096
+    )
+
+    assert not _validate_ssl_files(cert, key, extra_ca_certs={"root.crt": root[0]}).warnings
+
+    warnings = _validate_ssl_files(cert, key, extra_ca_certs={"other.crt": other_root[0]}).warnings
+    assert len(warnings) == 1
+    assert warnings[0].startswith(
+        "SSL certificate (subject /CN=someserver, issued by /CN=someroot) could not be verified "
+        + "against the system trust store or the extra CA certificates: "
+    )
+
+
+def test_validate_ssl_extra_ca_not_ca(app):
+    root = generate_test_cert(hostname="someroot", is_ca=True, key_usage=b"keyCertSign")
+    (cert, key) = generate_test_cert(
+        hostname="someserver", expires=60 * 60 * 24 * 90, issuer=root, key_size=4096
+    )
+    leaf = generate_test_cert(hostname="someleaf")
+    no_sign_root = generate_test_cert(
+        hostname="somenosignroot", is_ca=True, key_usage=b"digitalSignature"
+    )
+
+    extra_ca_certs = {"root.crt": root[0], "bundle.crt": leaf[0] + no_sign_root[0]}
+    warnings = _validate_ssl_files(cert, key, extra_ca_certs=extra_ca_certs).warnings
+    assert warnings == [
+        "Extra CA certificate bundle.crt (subject /CN=someleaf) is not a CA certificate, so it "
+        + "cannot be used to verify other certificates",
+        "Extra CA certificate bundle.crt (subject /CN=somenosignroot) is a CA, but its key usage "
+        + "does not permit signing certificates",
+    ]
+
+
+def test_validate_ssl_invalid_extra_ca(app):
+    (cert, key) = generate_test_cert(hostname="someserver")
+
+    with pytest.raises(ConfigValidationException) as ipe:
+        extra_ca_certs = {"broken.crt": b"-----BEGIN CERTIFICATE-----"}
+        _validate_ssl_files(cert, key, extra_ca_certs=extra_ca_certs)
+
+    assert str(ipe.value) == (
+        "Could not load extra CA certificate broken.crt: PEM block 1 is missing its END line"
+    )
+
+
+def test_validate_ssl_extra_ca_bundle(app):
+    (cert, key) = generate_test_cert(hostname="someserver")
+    roots = [generate_test_cert(hostname="someroot%s" % i, is_ca=True)[0] for i in range(3)]
+    bad_block = b"-----BEGIN CERTIFICATE-----\nnot!base64\n-----END CERTIFICATE-----\n"
+    private_key_block = generate_test_cert()[1]
+
+    _validate_ssl_files(cert, key, extra_ca_certs={"bundle.crt": b"".join(roots)})
+
+    for bundle, error_message in [
+        (
+            roots[0] + bad_block + roots[2],
+            "Could not load extra CA certificate bundle.crt: PEM block 2 could not be decoded",
+        ),
+        (
+            roots[0] + private_key_block + roots[2],
+            "Could not load extra CA certificate bundle.crt: PEM block 2 is of type PRIVATE KEY; "
+            + "expected a CERTIFICATE",
+        ),
+        (
+            b"".join(roots) + b"MIIBIjANBgkqhkiG9w0BAQEFAAOC\n",
+            "Extra CA certificate bundle.crt has unexpected data after its last PEM certificate",
+        ),
+        (
+            b"MIIBIjANBgkqhkiG9w0BAQEFAAOC\n",
+            "Extra CA certificate bundle.crt does not contain any PEM certificates",
+        ),
+    ]:
+        with pytest.raises(ConfigValidationException) as ipe:
+            _validate_ssl_files(cert, key, extra_ca_certs={"bundle.crt": bundle})
+
+        assert str(ipe.value) == error_message
+
+
+def test_validate_ssl_byte_order_mark(app):
+    (cert, key) = generate_test_cert(hostname="someserver")
+
+    with pytest.raises(ConfigValidationException) as ipe:
+        _validate_ssl_files(codecs.BOM_UTF8 + cert, key)
+
+    assert str(ipe.value) == (
+        "ssl.cert starts with a UTF-8 byte order mark; re-save it as UTF-8 without a BOM"
+    )
+
+
+def test_validate_ssl_non_utf8(app):
+    (cert, key) = generate_test_cert(hostname="someserver")
+
+    with pytest.raises(ConfigValidationException) as ipe:
+        _validate_ssl_files(cert, b"\xff" + key)
+
+    assert str(ipe.value) == "ssl.key contains non-UTF-8 data at byte 0; re-save it as UTF-8"
+
+    with pytest.raises(ConfigValidationException) as ipe:
+        extra_ca_certs = {"root.crt": codecs.BOM_UTF16_LE + cert}
+        _validate_ssl_files(cert, key, extra_ca_certs=extra_ca_certs)
+
+    assert str(ipe.value) == (
+        "extra_ca_certs/root.crt starts with a UTF-16 byte order mark; "
+        + "re-save it as UTF-8 without a BOM"
+    )
+
+
+def test_validate_ssl_weak_rsa_key(app):
+    (cert, key) = generate_test_cert(hostname="someserver", key_size=1024)
+
+    with pytest.raises(ConfigValidationException) as ipe:
+        _validate_ssl_files(cert, key)
+
+    assert str(ipe.value) == (
+        "SSL private key is a 1024-bit RSA key; at least 2048 bits are required"
+    )
+
+
+def test_validate_ssl_rsa_key_size_warning(app):
+    (cert, key) = generate_test_cert(hostname="someserver", key_size=2048)
+    assert (
+        "SSL private key is a 2048-bit RSA key; 4096 bits are recommended"
+        in _validate_ssl_files(cert, key).warnings
+    )
+
+    (cert, key) = generate_test_cert(hostname="someserver", key_size=4096)
+    assert not [w for w in _validate_ssl_files(cert, key).warnings if "private key" in w]
+
+
+@pytest.mark.parametrize(
+    "curve, error_message",
+    [
+        (
+            ec.SECP224R1(),
+            "SSL private key is an EC key on the 224-bit secp224r1 curve; a curve of at least "
+            + "256 bits is required",
+        ),
+        (ec.SECP256R1(), None),
+        (ec.SECP384R1(), None),
+    ],
+)
+def test_validate_ssl_ec_key(curve, error_message, app):
+    (cert, key) = generate_test_cert(hostname="someserver", ec_curve=curve)
+
+    if error_message is not None:
+        with pytest.raises(ConfigValidationException) as ipe:
+            _validate_ssl_files(cert, key)
+
+        assert str(ipe.value) == error_message
+    else:
+        assert not [w for w in _validate_ssl_files(cert, key).warnings if "private key" in w]
+
+
+@pytest.mark.parametrize(
+    "protocols, error_message",
+    [
+        (["TLSv1.2", "TLSv1.3"], None),
+        (["TLSv1.3"], None),
+        ([], "SSL_PROTOCOLS must enable at least one protocol"),
+        (
+            ["TLSv1.2", "TLS1.3"],
+            "Unknown protocol(s) in SSL_PROTOCOLS: TLS1.3. Expected one of: SSLv2, SSLv3, TLSv1, "
+            + "TLSv1.1, TLSv1.2, TLSv1.3",
+        ),
+        (
+            ["TLSv1", "TLSv1.1", "TLSv1.2"],
+            "Insecure protocol(s) in SSL_PROTOCOLS: TLSv1, TLSv1.1. TLSv1.2 or later is required",
+        ),
+    ],
+)
+def test_validate_ssl_protocols(protocols, error_message, app):
+    config = ValidatorContext(
+        {
+            "PREFERRED_URL_SCHEME": "https",
+            "SERVER_HOSTNAME": "someserver",
+            "SSL_PROTOCOLS": protocols,
+        }
+    )
+    config.config_provider = config_provider
+
+    # Stop once the protocols have been validated, at the SSL file checks.
+    with patch("app.config_provider.volume_file_exists", return_value=False):
+        with pytest.raises(ConfigValidationException) as ipe:
+            SSLValidator.validate(config)
+
+    assert str(ipe.value) == (error_message or MISSING_SSL_FILES)
+
+
+@pytest.mark.parametrize(
+    "ciphers, error_message, warnings",
+    [
+        (["ECDHE-RSA-AES128-GCM-SHA256", "AES", "!3DES", "-RC4"], None, []),
+        (["TLS_AES_128_GCM_SHA256", "ECDHE-RSA-AES128-GCM-SHA256"], None, []),
+        (
+            ["ECDHE-RSA-AES128-GCM-SHA256", "NOT-A-CIPHER", "ALSO-NOT-A-CIPHER"],
+            "Unknown cipher(s) in SSL_CIPHERS: NOT-A-CIPHER, ALSO-NOT-A-CIPHER",
+            [],
+        ),
+        (
+            ["ECDHE-RSA-AES128-GCM-SHA256", "DES-CBC3-SHA", "RC4-MD5", "eNULL"],
+            None,
+            ["Insecure cipher(s) enabled in SSL_CIPHERS: DES-CBC3-SHA, RC4-MD5, eNULL"],
+        ),
+    ],
+)
+def test_validate_ssl_ciphers(ciphers, error_message, warnings, app):
+    config = ValidatorContext(
+        {
+            "PREFERRED_URL_SCHEME": "https",
+            "SERVER_HOSTNAME": "someserver",
+            "SSL_CIPHERS": ciphers,
+        }
+    )
+    config.config_provider = config_provider
+
+    # Stop once the ciphers have been validated, at the SSL file checks.
+    with patch("app.config_provider.volume_file_exists", return_value=False):
+        with pytest.raises(ConfigValidationException) as ipe:
+            SSLValidator.validate(config)
+
+    assert str(ipe.value) == (error_message or MISSING_SSL_FILES)
+    assert config.warnings == warnings
+
+
+def test_validate_ssl_expiry_warning(app):
+    def expiry_warnings(cert, key):
+        warnings = _validate_ssl_files(cert, key).warnings
+        return [w for w in warnings if w.startswith("The specified SSL certificate expires")]
+
+    (cert, key) = generate_test_cert(hostname="someserver", expires=60 * 60 * 24 * 90)
+    assert not expiry_warnings(cert, key)
+
+    (cert, key) = generate_test_cert(hostname="someserver", expires=60 * 60 * 24 * 10)
+    warnings = expiry_warnings(cert, key)
+    assert len(warnings) == 1
+    assert warnings[0].startswith("The specified SSL certificate expires in 9 day(s)")
+
+
+def test_validate_ssl_not_yet_valid(app):
+    (cert, key) = generate_test_cert(hostname="someserver", not_before=60 * 60)
+
+    with pytest.raises(ConfigValidationException) as ipe:
+        _validate_ssl_files(cert, key)
+
+    assert str(ipe.value).startswith("The specified SSL certificate is not valid until ")
+
+
+@pytest.mark.parametrize(
+    "extra_block, error_message",
+    [
+        (
+            b"-----BEGIN CERTIFICATE-----\nnot!base64\n-----END CERTIFICATE-----\n",
+            "Could not load SSL certificate: PEM block 2 could not be decoded",
+        ),
+        (
+            b"-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n",
+            "Could not load SSL certificate block 2: ",
+        ),
+        (
+            b"-----BEGIN X509 CRL-----\nAAAA\n-----END X509 CRL-----\n",
+            "SSL certificate block 2 is of type X509 CRL; expected a CERTIFICATE",
+        ),
+    ],
+)
+def test_validate_ssl_invalid_chain(extra_block, error_message, app):
+    (cert, key) = generate_test_cert(hostname="someserver")
+
+    with pytest.raises(ConfigValidationException) as ipe:
+        _validate_ssl_files(cert + extra_block, key)
+
+    assert str(ipe.value).startswith(error_message)
@@ -1,10 +1,13 @@
+import boto3
 import pytest
 
+from mock import patch
 from moto import mock_s3
 
+from storage.fakestorage import FakeStorage, _GLOBAL_FAKE_STORAGE_MAP
 from util.config.validator import ValidatorContext
 from util.config.validators import ConfigValidationException
-from util.config.validators.validate_storage import StorageValidator
+from util.config.validators.validate_storage import StorageValidator, MAX_STORAGE_LOCATIONS
 
 from test.fixtures import *
 
@@ -27,6 +30,29 @@ def test_validate_storage(unvalidated_config, expected, app):
         validator.validate(ValidatorContext(unvalidated_config))
 
 
+def test_validate_storage_removes_verification_file(app):
+    validator = StorageValidator()
+    validator.validate(
+        ValidatorContext({"DISTRIBUTED_STORAGE_CONFIG": {"local": ["FakeStorage", {}]}})
+    )
+
+    assert "_verify" not in _GLOBAL_FAKE_STORAGE_MAP
+
+
+def test_validate_storage_corrupted_read(app):
+    validator = StorageValidator()
+    with patch.object(FakeStorage, "get_content", return_value=b"corrupted"):
+        with pytest.raises(ConfigValidationException) as ipe:
+            validator.validate(
+                ValidatorContext({"DISTRIBUTED_STORAGE_CONFIG": {"local": ["FakeStorage", {}]}})
+            )
+
+    assert str(ipe.value) == (
+        "Invalid storage configuration: local: Verification file contents do not match the "
+        + "contents written"
+    )
+
+
 def test_validate_s3_storage(app):
     validator = StorageValidator()
     with mock_s3():
@@ -53,3 +79,118 @@ def test_validate_s3_storage(app):
             str(ipe.value)
             == "Invalid storage configuration: default: An error occurred (404) when calling the HeadBucket operation: Not Found"
         )
+
+
+@pytest.mark.parametrize(
+    "preference, default_locations, expected_error",
+    [
+        (["local"], ["local"], None),
+        (
+            ["local", "remote"],
+            [],
+            "Storage location(s) in DISTRIBUTED_STORAGE_PREFERENCE not found in storage config: "
+            + "remote",
+        ),
+        (
+            ["local"],
+            ["unknown", "other"],
+            "Storage location(s) in DISTRIBUTED_STORAGE_DEFAULT_LOCATIONS not found in storage "
+            + "config: unknown, other",
+        ),
+    ],
+)
+def test_validate_storage_location_references(preference, default_locations, expected_error, app):
+    validator = StorageValidator()
+    config = ValidatorContext(
+        {
+            "DISTRIBUTED_STORAGE_CONFIG": {"local": ["FakeStorage", {}]},
+            "DISTRIBUTED_STORAGE_PREFERENCE": preference,
+            "DISTRIBUTED_STORAGE_DEFAULT_LOCATIONS": default_locations,
+        }
+    )
+
+    if expected_error is not None:
+        with pytest.raises(ConfigValidationException) as ipe:
+            validator.validate(config)
+
+        assert str(ipe.value) == expected_error
+    else:
+        validator.validate(config)
+        assert not config.warnings
+
+
+def test_validate_storage_duplicate_locations(app):
+    validator = StorageValidator()
+    config = ValidatorContext(
+        {
+            "DISTRIBUTED_STORAGE_CONFIG": {"local": ["FakeStorage", {}]},
+            "DISTRIBUTED_STORAGE_PREFERENCE": ["local", "local"],
+        }
+    )
+
+    validator.validate(config)
+    assert config.warnings == [
+        "Duplicate storage location(s) in DISTRIBUTED_STORAGE_PREFERENCE: local"
+    ]
+
+
+def test_validate_storage_location_count(app):
+    validator = StorageValidator()
+
+    storage_config = {"local%s" % i: ["FakeStorage", {}] for i in range(0, MAX_STORAGE_LOCATIONS)}
+    config = ValidatorContext({"DISTRIBUTED_STORAGE_CONFIG": storage_config})
+    validator.validate(config)
+    assert not config.warnings
+
+    storage_config["onetoomany"] = ["FakeStorage", {}]
+    config = ValidatorContext({"DISTRIBUTED_STORAGE_CONFIG": storage_config})
+    validator.validate(config)
+    assert config.warnings == [
+        "11 storage locations are configured; more than 10 can overwhelm replication"
+    ]
+
+
+def _s3_storage(bucket, path):
+    return [
+        "S3Storage",
+        {
+            "s3_access_key": "somekey",
+            "s3_secret_key": "somesecret",
+            "s3_bucket": bucket,
+            "storage_path": path,
+        },
+    ]
+
+
+@pytest.mark.parametrize(
+    "storage_config, expected_warnings",
+    [
+        (
+            {
+                "first": _s3_storage("somebucket", "/first"),
+                "second": _s3_storage("somebucket", "/second"),
+                "third": _s3_storage("otherbucket", "/first"),
+            },
+            [],
+        ),
+        (
+            {
+                "first": _s3_storage("somebucket", "/images"),
+                "second": _s3_storage("somebucket", "images/"),
+            },
+            ["Storage locations first, second share bucket `somebucket` and path `/images`"],
+        ),
+    ],
+)
+def test_validate_storage_duplicate_buckets(storage_config, expected_warnings, app):
+    validator = StorageValidator()
+    config = ValidatorContext({"DISTRIBUTED_STORAGE_CONFIG": storage_config})
+
+    with mock_s3():
+        s3 = boto3.client("s3", region_name="us-east-1")
+        s3.create_bucket(Bucket="somebucket")
+        s3.create_bucket(Bucket="otherbucket")
+
+        validator.validate(config)
+
+    assert config.warnings == expected_warnings
@@ -23,7 +23,21 @@ from test.fixtures import *
     [
         ("2d", ["1w", "2d"], None),
         ("2d", ["1w"], "Default expiration must be in expiration options set"),
-        ("2d", ["2d", "1M"], "Invalid tag expiration option: 1M"),
+        (
+            "2d",
+            ["2d", "1M"],
+            "Invalid tag expiration option `1M`: expected a number followed by a unit (s for "
+            + "seconds, m for minutes, h for hours, d for days or w for weeks), such as 30m, 2w "
+            + "or 1h30m",
+        ),
+        ("1h30m", ["1h30m", "2d"], None),
+        (
+            "2 days",
+            ["2 days"],
+            "Invalid default expiration `2 days`: expected a number followed by a unit (s for "
+            + "seconds, m for minutes, h for hours, d for days or w for weeks), such as 30m, 2w "
+            + "or 1h30m",
+        ),
     ],
 )
 def test_validate(default_exp, options, expected_exception, app):
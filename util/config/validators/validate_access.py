@@ -1,6 +1,7 @@
 from util.config.validators import BaseValidator, ConfigValidationException
 from oauth.loginmanager import OAuthLoginManager
 from oauth.oidc import OIDCLoginService
+from util.timedeltastring import convert_to_timedelta, DURATION_FORMAT_DESCRIPTION
 
 
 class AccessSettingsValidator(BaseValidator):
@@ -28,3 +29,25 @@ class AccessSettingsValidator(BaseValidator):
         ):
             msg = "Invite only user creation requires user creation to be enabled"
             raise ConfigValidationException(msg)
+
+        # Make sure a fresh login is not required less often than sessions expire.
+        fresh_login_timeout = _parse_duration(config, "FRESH_LOGIN_TIMEOUT", "10m")
+        if config.get("FEATURE_PERMANENT_SESSIONS", True):
+            session_timeout = _parse_duration(config, "SESSION_TIMEOUT", "31d")
+            if fresh_login_timeout > session_timeout:
+                validator_context.add_warning(
+                    "FRESH_LOGIN_TIMEOUT (%s) is longer than SESSION_TIMEOUT (%s), so sessions "
+                    "will expire before a fresh login is ever required"
+                    % (
+                        config.get("FRESH_LOGIN_TIMEOUT", "10m"),
+                        config.get("SESSION_TIMEOUT", "31d"),
+                    )
+                )
+
+
+def _parse_duration(config, key, default):
+    try:
+        return convert_to_timedelta(config.get(key, default))
+    except ValueError:
+        msg = "Invalid %s `%s`: expected %s" % (key, config[key], DURATION_FORMAT_DESCRIPTION)
+        raise ConfigValidationException(msg)
@@ -1,4 +1,5 @@
 from util.config.validators import BaseValidator, ConfigValidationException
+from util.timedeltastring import convert_to_timedelta, DURATION_FORMAT_DESCRIPTION
 
 
 class AppTokenAuthValidator(BaseValidator):
@@ -20,3 +21,25 @@ class AppTokenAuthValidator(BaseValidator):
         if config.get("FEATURE_DIRECT_LOGIN", True):
             msg = "Direct login must be disabled to use External Application Token auth"
             raise ConfigValidationException(msg)
+
+        # Ensure that the default token expiration, if any, is a valid duration.
+        expiration = config.get("APP_SPECIFIC_TOKEN_EXPIRATION")
+        if not expiration:
+            validator_context.add_warning(
+                "APP_SPECIFIC_TOKEN_EXPIRATION is not set; external application tokens will not "
+                + "expire by default"
+            )
+            return
+
+        try:
+            duration = convert_to_timedelta(expiration)
+        except ValueError:
+            msg = "Invalid APP_SPECIFIC_TOKEN_EXPIRATION `%s`: expected %s" % (
+                expiration,
+                DURATION_FORMAT_DESCRIPTION,
+            )
+            raise ConfigValidationException(msg)
+
+        if duration.total_seconds() <= 0:
+            msg = "APP_SPECIFIC_TOKEN_EXPIRATION must be a positive duration"
+            raise ConfigValidationException(msg)
@@ -1,6 +1,12 @@
+import requests
+
 from bitbucket import BitBucket
 
-from util.config.validators import BaseValidator, ConfigValidationException
+from util.config.validators import (
+    BaseValidator,
+    ConfigValidationException,
+    warn_if_internal_hostname,
+)
 
 
 class BitbucketTriggerValidator(BaseValidator):
@@ -30,6 +36,16 @@ class BitbucketTriggerValidator(BaseValidator):
         )
 
         bitbucket_client = BitBucket(key, secret, callback_url)
-        (result, _, _) = bitbucket_client.get_authorization_url()
+        try:
+            (result, _, err_msg) = bitbucket_client.get_authorization_url()
+        except requests.exceptions.RequestException as rex:
+            raise ConfigValidationException("Could not connect to Bitbucket: %s" % rex)
+
         if not result:
-            raise ConfigValidationException("Invalid consumer key or secret")
+            msg = "Invalid consumer key or secret"
+            if err_msg:
+                msg = "%s: %s" % (msg, err_msg)
+
+            raise ConfigValidationException(msg)
+
+        warn_if_internal_hostname(validator_context, "Bitbucket")
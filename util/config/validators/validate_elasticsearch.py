@@ -1,9 +1,13 @@
 import requests
 from elasticsearch_dsl.connections import connections
+from requests_aws4auth import AWS4Auth
 
 from data.logs_model.elastic_logs import INDEX_NAME_PREFIX
 from util.config.validators import BaseValidator, ConfigValidationException
 
+# The timeout, in seconds, for requests made to Elasticsearch.
+ELASTICSEARCH_VALIDATION_TIMEOUT = 10
+
 
 class ElasticsearchValidator(BaseValidator):
     name = "elasticsearch"
@@ -27,10 +31,55 @@ class ElasticsearchValidator(BaseValidator):
         host = elasticsearch_config["host"]
         port = str(elasticsearch_config["port"])
         index_prefix = elasticsearch_config.get("index_prefix") or INDEX_NAME_PREFIX
-        auth = (elasticsearch_config.get("access_key"), elasticsearch_config.get("secret_key"))
+        scheme = "https" if elasticsearch_config.get("use_ssl", True) else "http"
+        base_url = "%s://%s:%s" % (scheme, host, port)
+
+        # Authenticate the same way as the Elasticsearch logs model: AWS request signing if a region
+        # is given, otherwise basic auth.
+        access_key = elasticsearch_config.get("access_key")
+        secret_key = elasticsearch_config.get("secret_key")
+        aws_region = elasticsearch_config.get("aws_region")
+        if access_key and secret_key and aws_region:
+            auth = AWS4Auth(access_key, secret_key, aws_region, "es")
+        elif access_key and secret_key:
+            auth = (access_key, secret_key)
+        else:
+            auth = None
+
+        resp = _get(base_url, "/_cluster/health", auth)
+        try:
+            cluster_status = resp.json().get("status")
+        except (ValueError, AttributeError):
+            cluster_status = None
+
+        if cluster_status == "red":
+            msg = "Elasticsearch cluster at %s has status red; primary shards are unassigned"
+            raise ConfigValidationException(msg % base_url)
+
+        if cluster_status == "yellow":
+            msg = "Elasticsearch cluster at %s has status yellow; replica shards are unassigned"
+            validator_context.add_warning(msg % base_url)
+
+        _get(base_url, "/" + index_prefix + "*", auth)
+
+
+def _get(base_url, path, auth):
+    """
+    Performs a GET request against Elasticsearch and returns the response, raising a
+    ConfigValidationException if the request fails.
+    """
+    try:
+        resp = requests.get(base_url + path, auth=auth, timeout=ELASTICSEARCH_VALIDATION_TIMEOUT)
+    except requests.exceptions.RequestException as rex:
+        msg = "Could not connect to Elasticsearch at %s: %s" % (base_url, rex)
+        raise ConfigValidationException(msg)
+
+    if resp.status_code in (401, 403):
+        msg = "Elasticsearch at %s rejected the configured credentials with HTTP %s"
+        raise ConfigValidationException(msg % (base_url, resp.status_code))
+
+    if resp.status_code != 200:
+        msg = "Unable to connect to Elasticsearch at %s: GET %s returned HTTP %s"
+        raise ConfigValidationException(msg % (base_url, path, resp.status_code))
 
-        resp = requests.get("https://" + host + ":" + port + "/" + index_prefix + "*", auth=auth)
-        if resp.status_code != 200:
-            raise ConfigValidationException(
-                "Unable to connect to Elasticsearch with config: %s", resp.status_code
-            )
+    return resp
@@ -0,0 +1,104 @@
+import smtplib
+import socket
+import ssl
+
+from util.config.validators import BaseValidator, ConfigValidationException
+
+# The timeout, in seconds, for connecting to and talking with the SMTP server.
+SMTP_TIMEOUT = 10
+
+# The SMTP AUTH mechanisms supported by smtplib, and therefore by Flask-Mail.
+SUPPORTED_AUTH_MECHANISMS = ["CRAM-MD5", "PLAIN", "LOGIN"]
+
+
+class EmailValidator(BaseValidator):
+    name = "mail"
+
+    @classmethod
+    def validate(cls, validator_context):
+        """
+        Validates that the SMTP server can be reached and, if credentials are configured, that it
+        accepts them.
+
+        No e-mail is sent.
+        """
+        config = validator_context.config
+
+        if not config.get("FEATURE_MAILING", True):
+            return
+
+        server = config.get("MAIL_SERVER")
+        if not server:
+            raise ConfigValidationException("Missing MAIL_SERVER")
+
+        # Note: the defaults match those in config.py, rather than those of Flask-Mail.
+        port = config.get("MAIL_PORT", 587)
+        use_ssl = config.get("MAIL_USE_SSL", False)
+        use_tls = config.get("MAIL_USE_TLS", True)
+        username = config.get("MAIL_USERNAME")
+        password = config.get("MAIL_PASSWORD")
+        address = "%s:%s" % (server, port)
+
+        smtp_class = smtplib.SMTP_SSL if use_ssl else smtplib.SMTP
+        try:
+            smtp = smtp_class(server, port, timeout=SMTP_TIMEOUT)
+        except ssl.SSLError as sse:
+            msg = "TLS error when connecting to SMTP server %s: %s" % (address, sse)
+            raise ConfigValidationException(msg)
+        except (socket.error, smtplib.SMTPException) as ex:
+            msg = "Could not connect to SMTP server %s: %s" % (address, ex)
+            raise ConfigValidationException(msg)
+
+        try:
+            if use_tls and not use_ssl:
+                _starttls(smtp, address)
+
+            if username:
+                _login(smtp, address, username, password)
+        finally:
+            smtp.close()
+
+
+def _starttls(smtp, address):
+    """
+    Upgrades the SMTP connection to TLS, as Flask-Mail does when MAIL_USE_TLS is set.
+    """
+    try:
+        smtp.starttls()
+    except smtplib.SMTPNotSupportedError:
+        msg = "SMTP server %s does not support STARTTLS; disable MAIL_USE_TLS or use MAIL_USE_SSL"
+        raise ConfigValidationException(msg % address)
+    except (ssl.SSLError, smtplib.SMTPResponseException) as ex:
+        raise ConfigValidationException("TLS error with SMTP server %s: %s" % (address, ex))
+
+
+def _login(smtp, address, username, password):
+    """
+    Logs into the SMTP server with the configured credentials.
+    """
+    smtp.ehlo_or_helo_if_needed()
+    if not smtp.has_extn("auth"):
+        msg = "SMTP server %s does not support authentication; remove MAIL_USERNAME"
+        raise ConfigValidationException(msg % address)
+
+    # smtplib only supports some mechanisms and otherwise fails with a generic error, so check the
+    # advertised mechanisms first.
+    mechanisms = smtp.esmtp_features["auth"].upper().split()
+    if not set(mechanisms) & set(SUPPORTED_AUTH_MECHANISMS):
+        msg = "SMTP server %s offers no supported AUTH mechanism (offered: %s); expected one of %s"
+        raise ConfigValidationException(
+            msg % (address, ", ".join(mechanisms), ", ".join(SUPPORTED_AUTH_MECHANISMS))
+        )
+
+    try:
+        smtp.login(username, password or "")
+    except smtplib.SMTPAuthenticationError as sae:
+        msg = "SMTP server %s rejected the credentials for %s: %s %s" % (
+            address,
+            username,
+            sae.smtp_code,
+            sae.smtp_error.decode("utf-8", "replace"),
+        )
+        raise ConfigValidationException(msg)
+    except smtplib.SMTPException as se:
+        raise ConfigValidationException("Could not login to SMTP server %s: %s" % (address, se))
@@ -1,10 +1,15 @@
 from oauth.services.github import GithubOAuthService
-from util.config.validators import BaseValidator, ConfigValidationException
+from util.config.validators import (
+    BaseValidator,
+    ConfigValidationException,
+    warn_if_internal_hostname,
+)
 
 
 class BaseGitHubValidator(BaseValidator):
     name = None
     config_key = None
+    receives_webhooks = False
 
     @classmethod
     def validate(cls, validator_context):
@@ -26,6 +31,12 @@ class BaseGitHubValidator(BaseValidator):
         if endpoint.find("http://") != 0 and endpoint.find("https://") != 0:
             raise ConfigValidationException("Github Endpoint must start with http:// or https://")
 
+        # GitHub Enterprise installations may serve their API from a separate endpoint.
+        api_endpoint = github_config.get("API_ENDPOINT")
+        if api_endpoint is not None and not api_endpoint.startswith(("http://", "https://")):
+            msg = "Github API Endpoint must start with http:// or https://"
+            raise ConfigValidationException(msg)
+
         if not github_config.get("CLIENT_ID"):
             raise ConfigValidationException("Missing Client ID")
 
@@ -47,6 +58,9 @@ class BaseGitHubValidator(BaseValidator):
                 if not oauth.validate_organization(org_id, client):
                     raise ConfigValidationException("Invalid organization: %s" % org_id)
 
+        if cls.receives_webhooks:
+            warn_if_internal_hostname(validator_context, "GitHub")
+
 
 class GitHubLoginValidator(BaseGitHubValidator):
     name = "github-login"
@@ -56,3 +70,4 @@ class GitHubLoginValidator(BaseGitHubValidator):
 class GitHubTriggerValidator(BaseGitHubValidator):
     name = "github-trigger"
     config_key = "GITHUB_TRIGGER_CONFIG"
+    receives_webhooks = True
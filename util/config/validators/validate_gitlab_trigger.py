@@ -1,5 +1,11 @@
+import requests
+
 from oauth.services.gitlab import GitLabOAuthService
-from util.config.validators import BaseValidator, ConfigValidationException
+from util.config.validators import (
+    BaseValidator,
+    ConfigValidationException,
+    warn_if_internal_hostname,
+)
 
 
 class GitLabTriggerValidator(BaseValidator):
@@ -32,6 +38,20 @@ class GitLabTriggerValidator(BaseValidator):
             raise ConfigValidationException("Missing Client Secret")
 
         oauth = GitLabOAuthService(config, "GITLAB_TRIGGER_CONFIG")
-        result = oauth.validate_client_id_and_secret(client, url_scheme_and_hostname)
+        gitlab_endpoint = oauth.api_endpoint()
+        try:
+            result = oauth.validate_client_id_and_secret(client, url_scheme_and_hostname)
+        except requests.exceptions.SSLError as ssle:
+            msg = "TLS error when connecting to GitLab at %s: %s" % (gitlab_endpoint, ssle)
+            raise ConfigValidationException(msg)
+        except requests.exceptions.ConnectionError as ce:
+            msg = "Could not connect to GitLab at %s: %s" % (gitlab_endpoint, ce)
+            raise ConfigValidationException(msg)
+        except ValueError:
+            msg = "%s did not return a valid OAuth response; is it a GitLab endpoint?"
+            raise ConfigValidationException(msg % gitlab_endpoint)
+
         if not result:
             raise ConfigValidationException("Invalid client id or client secret")
+
+        warn_if_internal_hostname(validator_context, "GitLab")
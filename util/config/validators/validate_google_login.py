@@ -1,6 +1,10 @@
+import requests
+
 from oauth.services.google import GoogleOAuthService
 from util.config.validators import BaseValidator, ConfigValidationException
 
+GOOGLE_CLIENT_ID_SUFFIX = ".apps.googleusercontent.com"
+
 
 class GoogleLoginValidator(BaseValidator):
     name = "google-login"
@@ -24,7 +28,19 @@ class GoogleLoginValidator(BaseValidator):
         if not google_login_config.get("CLIENT_SECRET"):
             raise ConfigValidationException("Missing Client Secret")
 
+        client_id = google_login_config["CLIENT_ID"]
+        if not client_id.endswith(GOOGLE_CLIENT_ID_SUFFIX) or client_id == GOOGLE_CLIENT_ID_SUFFIX:
+            msg = "Client ID `%s` is not a Google OAuth client ID; expected `<id>%s`" % (
+                client_id,
+                GOOGLE_CLIENT_ID_SUFFIX,
+            )
+            raise ConfigValidationException(msg)
+
         oauth = GoogleOAuthService(config, "GOOGLE_LOGIN_CONFIG")
-        result = oauth.validate_client_id_and_secret(client, url_scheme_and_hostname)
+        try:
+            result = oauth.validate_client_id_and_secret(client, url_scheme_and_hostname)
+        except requests.exceptions.RequestException as rex:
+            raise ConfigValidationException("Could not reach Google's OAuth endpoint: %s" % rex)
+
         if not result:
             raise ConfigValidationException("Invalid client id or client secret")
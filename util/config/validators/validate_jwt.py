@@ -1,4 +1,12 @@
 import os
+
+import requests
+
+from cryptography.exceptions import UnsupportedAlgorithm
+from cryptography.hazmat.backends import default_backend
+from cryptography.hazmat.primitives import serialization
+from cryptography.hazmat.primitives.asymmetric import rsa
+
 from data.users.externaljwt import ExternalJWTAuthN
 from util.config.validators import BaseValidator, ConfigValidationException
 
@@ -47,6 +55,20 @@ class JWTAuthValidator(BaseValidator):
             requires_email=config.get("FEATURE_MAILING", True),
         )
 
+        # Verify that the public key can verify the RS256 tokens issued by the JWT server.
+        _validate_public_key(users.public_key)
+
+        # Verify that each configured endpoint can be reached.
+        for endpoint in [verify_endpoint, query_endpoint, getuser_endpoint]:
+            if endpoint is None:
+                continue
+
+            try:
+                http_client.get(endpoint, timeout=2)
+            except requests.exceptions.RequestException as rex:
+                msg = "Could not reach JWT authentication endpoint %s: %s" % (endpoint, rex)
+                raise ConfigValidationException(msg)
+
         # Verify that we can reach the jwt server
         (result, err_msg) = users.ping()
         if not result:
@@ -55,3 +77,30 @@ class JWTAuthValidator(BaseValidator):
                 + "OR JWT auth is misconfigured"
             ) % err_msg
             raise ConfigValidationException(msg)
+
+
+def _validate_public_key(key_contents):
+    """
+    Raises a ConfigValidationException if the given JWT authentication public key is not an RSA
+    public key in PEM or OpenSSH format.
+    """
+    if b"-----BEGIN CERTIFICATE-----" in key_contents:
+        msg = (
+            "JWT authentication public key file contains a certificate rather than a public key; "
+            + "extract the key with `openssl x509 -pubkey -noout`"
+        )
+        raise ConfigValidationException(msg)
+
+    try:
+        if key_contents.lstrip().startswith(b"ssh-"):
+            public_key = serialization.load_ssh_public_key(
+                key_contents.strip(), backend=default_backend()
+            )
+        else:
+            public_key = serialization.load_pem_public_key(key_contents, backend=default_backend())
+    except (ValueError, UnsupportedAlgorithm) as ex:
+        raise ConfigValidationException("Could not load JWT authentication public key: %s" % ex)
+
+    if not isinstance(public_key, rsa.RSAPublicKey):
+        msg = "JWT authentication public key is not an RSA key; tokens are verified with RS256"
+        raise ConfigValidationException(msg)
@@ -0,0 +1,79 @@
+from kafka import KafkaConsumer
+from kafka.errors import KafkaError, NoBrokersAvailable
+
+from data.logs_model.logs_producer.kafka_logs_producer import DEFAULT_MAX_BLOCK_SECONDS
+from util.config.validators import BaseValidator, ConfigValidationException
+
+
+class KafkaValidator(BaseValidator):
+    name = "kafka"
+
+    @classmethod
+    def validate(cls, validator_context):
+        """
+        Validates that the Kafka brokers configured for action logs can be reached and that the
+        topic exists.
+        """
+        config = validator_context.config
+
+        logs_model = config.get("LOGS_MODEL", "database")
+        if logs_model != "elasticsearch":
+            raise ConfigValidationException("LOGS_MODEL not set to Elasticsearch")
+
+        logs_model_config = config.get("LOGS_MODEL_CONFIG", {})
+        producer = logs_model_config.get("producer", {})
+        if not producer or producer != "kafka":
+            raise ConfigValidationException("Producer not set to 'kafka'")
+
+        kafka_config = logs_model_config.get("kafka_config", {})
+        if not kafka_config:
+            raise ConfigValidationException("No kafka_config defined")
+
+        bootstrap_servers = kafka_config.get("bootstrap_servers")
+        if not bootstrap_servers:
+            raise ConfigValidationException("Missing bootstrap_servers in kafka_config")
+
+        if isinstance(bootstrap_servers, str):
+            bootstrap_servers = [bootstrap_servers]
+
+        topic = kafka_config.get("topic")
+        if not topic:
+            raise ConfigValidationException("Missing topic in kafka_config")
+
+        # Use the same timeout as the log producer when looking up metadata.
+        timeout_ms = (kafka_config.get("max_block_seconds") or DEFAULT_MAX_BLOCK_SECONDS) * 1000
+
+        # Connect to each broker separately, so that unreachable brokers can be reported.
+        topics = None
+        unreachable = []
+        for server in bootstrap_servers:
+            try:
+                consumer = KafkaConsumer(
+                    bootstrap_servers=[server],
+                    client_id=kafka_config.get("client_id"),
+                    api_version_auto_timeout_ms=timeout_ms,
+                )
+            except NoBrokersAvailable:
+                unreachable.append(server)
+                continue
+
+            try:
+                if topics is None:
+                    topics = consumer.topics()
+            except KafkaError as ke:
+                msg = "Could not list topics from Kafka broker %s: %s" % (server, ke)
+                raise ConfigValidationException(msg)
+            finally:
+                consumer.close()
+
+        if topics is None:
+            msg = "No Kafka brokers reachable; tried %s" % ", ".join(bootstrap_servers)
+            raise ConfigValidationException(msg)
+
+        if topic not in topics:
+            msg = "Kafka topic `%s` not found on the configured brokers"
+            raise ConfigValidationException(msg % topic)
+
+        if unreachable:
+            msg = "Some Kafka brokers are unreachable: %s"
+            validator_context.add_warning(msg % ", ".join(unreachable))
@@ -1,6 +1,8 @@
 from util.config.validators import BaseValidator, ConfigValidationException
 from data.users.keystone import get_keystone_users
 
+KEYSTONE_AUTH_VERSIONS = [2, 3]
+
 
 class KeystoneValidator(BaseValidator):
     name = "keystone"
@@ -16,7 +18,6 @@ class KeystoneValidator(BaseValidator):
             return
 
         auth_url = config.get("KEYSTONE_AUTH_URL")
-        auth_version = int(config.get("KEYSTONE_AUTH_VERSION", 2))
         admin_username = config.get("KEYSTONE_ADMIN_USERNAME")
         admin_password = config.get("KEYSTONE_ADMIN_PASSWORD")
         admin_tenant = config.get("KEYSTONE_ADMIN_TENANT")
@@ -33,11 +34,36 @@ class KeystoneValidator(BaseValidator):
         if not admin_tenant:
             raise ConfigValidationException("Missing admin tenant")
 
+        try:
+            auth_version = int(config.get("KEYSTONE_AUTH_VERSION", 2))
+        except (TypeError, ValueError):
+            auth_version = None
+
+        if auth_version not in KEYSTONE_AUTH_VERSIONS:
+            msg = "Unsupported Keystone auth version `%s`; expected 2 or 3"
+            raise ConfigValidationException(msg % config.get("KEYSTONE_AUTH_VERSION"))
+
         requires_email = config.get("FEATURE_MAILING", True)
         users = get_keystone_users(
-            auth_version, auth_url, admin_username, admin_password, admin_tenant, requires_email
+            auth_version,
+            auth_url,
+            admin_username,
+            admin_password,
+            admin_tenant,
+            requires_email=requires_email,
         )
 
+        # Verify that the admin can authenticate, so that an unreachable endpoint or invalid
+        # credentials are reported as such rather than as a missing user.
+        (result, err_msg) = users.ping()
+        if not result:
+            msg = "Could not authenticate to Keystone v%s at %s: %s" % (
+                auth_version,
+                auth_url,
+                err_msg,
+            )
+            raise ConfigValidationException(msg)
+
         # Verify that the superuser exists. If not, raise an exception.
         (result, err_msg) = users.at_least_one_user_exists()
         if not result:
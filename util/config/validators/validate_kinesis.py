@@ -1,6 +1,13 @@
 import boto3
 
+from botocore.client import Config
+from botocore.exceptions import BotoCoreError, ClientError
+
 from data.logs_model.elastic_logs import INDEX_NAME_PREFIX
+from data.logs_model.logs_producer.kinesis_stream_logs_producer import (
+    DEFAULT_CONNECT_TIMEOUT,
+    DEFAULT_READ_TIMEOUT,
+)
 from util.config.validators import BaseValidator, ConfigValidationException
 
 
@@ -29,20 +36,28 @@ class KinesisValidator(BaseValidator):
         aws_secret_key = kinesis_stream_config.get("aws_secret_key")
         aws_region = kinesis_stream_config.get("aws_region")
 
+        client_config = Config(
+            connect_timeout=kinesis_stream_config.get("connect_timeout") or DEFAULT_CONNECT_TIMEOUT,
+            read_timeout=kinesis_stream_config.get("read_timeout") or DEFAULT_READ_TIMEOUT,
+        )
         producer = boto3.client(
             "kinesis",
             use_ssl=True,
             region_name=aws_region,
             aws_access_key_id=aws_access_key,
             aws_secret_access_key=aws_secret_key,
+            config=client_config,
         )
 
         try:
             producer.describe_stream(StreamName=stream_name)
-        except (
-            producer.exceptions.ClientError,
-            producer.exceptions.ResourceNotFoundException,
-        ) as e:
-            raise ConfigValidationException("Unable to connect to Kinesis with config: %s", e)
-        except Exception:
-            raise ConfigValidationException("Unable to connect to Kinesis")
+        except ClientError as ce:
+            if ce.response.get("Error", {}).get("Code") == "ResourceNotFoundException":
+                msg = "Kinesis stream `%s` not found in region %s" % (stream_name, aws_region)
+                raise ConfigValidationException(msg)
+
+            msg = "Unable to describe Kinesis stream `%s`: %s" % (stream_name, ce)
+            raise ConfigValidationException(msg)
+        except BotoCoreError as bce:
+            msg = "Could not connect to Kinesis in region %s: %s" % (aws_region, bce)
+            raise ConfigValidationException(msg)
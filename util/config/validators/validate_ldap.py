@@ -43,6 +43,15 @@ class LDAPValidator(BaseValidator):
         if not ldap_uri.startswith("ldap://") and not ldap_uri.startswith("ldaps://"):
             raise ConfigValidationException("LDAP URI must start with ldap:// or ldaps://")
 
+        # Binding with the admin password over plain ldap:// sends it unencrypted. This is only a
+        # warning, as lab and test setups commonly run without TLS.
+        if ldap_uri.startswith("ldap://"):
+            msg = (
+                "LDAP URI %s does not use TLS, so the admin bind password is sent in plaintext. "
+                + "Consider using ldaps:// instead."
+            )
+            validator_context.add_warning(msg % ldap_uri)
+
         allow_tls_fallback = config.get("LDAP_ALLOW_INSECURE_FALLBACK", False)
 
         try:
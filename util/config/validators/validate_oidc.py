@@ -1,3 +1,5 @@
+import requests
+
 from oauth.loginmanager import OAuthLoginManager
 from oauth.oidc import OIDCLoginService, DiscoveryFailureException
 from util.config.validators import BaseValidator, ConfigValidationException
@@ -35,3 +37,25 @@ class OIDCLoginValidator(BaseValidator):
             except DiscoveryFailureException as dfe:
                 msg = "Could not validate OIDC service %s: %s" % (service.service_id(), str(dfe))
                 raise ConfigValidationException(msg)
+
+            # Not every provider supports the client credentials grant, so only a rejected client
+            # fails validation.
+            try:
+                error = service.validate_client_credentials(client)
+            except requests.exceptions.RequestException as rex:
+                msg = "Could not reach the token endpoint of OIDC service %s: %s" % (
+                    service.service_id(),
+                    rex,
+                )
+                raise ConfigValidationException(msg)
+
+            if error == "invalid_client":
+                msg = "Invalid client id or client secret for OIDC service %s"
+                raise ConfigValidationException(msg % service.service_id())
+
+            if error is not None:
+                msg = (
+                    "Could not verify the client secret of OIDC service %s: the token endpoint "
+                    + "returned `%s` for a client credentials grant"
+                )
+                validator_context.add_warning(msg % (service.service_id(), error))
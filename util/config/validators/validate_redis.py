@@ -1,7 +1,13 @@
+import re
+
 import redis
 
 from util.config.validators import BaseValidator, ConfigValidationException
 
+_REDACTED = "********"
+_PASSWORD_PARAM_REGEX = re.compile(r"(password=)[^\s&,;]+", re.IGNORECASE)
+_URL_USERINFO_REGEX = re.compile(r"([a-z][a-z0-9+.-]*://)[^/\s@]+@", re.IGNORECASE)
+
 
 class RedisValidator(BaseValidator):
     name = "redis"
@@ -18,4 +24,20 @@ class RedisValidator(BaseValidator):
             raise ConfigValidationException("Missing redis hostname")
 
         client = redis.StrictRedis(socket_connect_timeout=5, **redis_config)
-        client.ping()
+        try:
+            client.ping()
+        except redis.RedisError as rex:
+            msg = _sanitize_redis_error(str(rex), redis_config.get("password"))
+            raise ConfigValidationException("Could not connect to redis: %s" % msg)
+
+
+def _sanitize_redis_error(message, password):
+    """
+    Strips the configured password, any `password=` parameters and any URL userinfo from a redis
+    error message, so that credentials are never returned in a validation result.
+    """
+    if password:
+        message = message.replace(str(password), _REDACTED)
+
+    message = _PASSWORD_PARAM_REGEX.sub(r"\g<1>" + _REDACTED, message)
+    return _URL_USERINFO_REGEX.sub(r"\g<1>" + _REDACTED + "@", message)
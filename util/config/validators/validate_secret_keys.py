@@ -0,0 +1,118 @@
+import math
+import uuid
+
+from collections import Counter
+
+from util.config.validators import BaseValidator, ConfigValidationException
+
+# The secret key settings to validate.
+SECRET_KEY_FIELDS = ["SECRET_KEY", "DATABASE_SECRET_KEY"]
+
+# The minimum length of a secret key, in characters.
+MIN_SECRET_KEY_LENGTH = 40
+
+# The minimum and recommended estimated entropy of a secret key, in bits.
+MIN_SECRET_KEY_ENTROPY_BITS = 64
+RECOMMENDED_SECRET_KEY_ENTROPY_BITS = 128
+
+# Placeholder values that show up in example configurations.
+WEAK_SECRET_KEYS = {"", "secret", "secretkey", "secret_key", "changeme", "password", "quay"}
+
+REMEDIATION = "Generate a new key with `openssl rand -hex 40`"
+
+# Fields encrypted with DATABASE_SECRET_KEY cannot be read with a different key.
+REMEDIATIONS = {
+    "DATABASE_SECRET_KEY": REMEDIATION
+    + "; note that existing encrypted fields cannot be decrypted after changing it",
+}
+
+
+class SecretKeyValidator(BaseValidator):
+    name = "secret-keys"
+
+    @classmethod
+    def validate(cls, validator_context):
+        """
+        Validates that the configured secret keys are long and random enough to sign sessions and
+        encrypt database fields.
+        """
+        config = validator_context.config
+
+        for field in SECRET_KEY_FIELDS:
+            # Quay generates the key at startup if it is not set.
+            if config.get(field) is None:
+                continue
+
+            _validate_secret_key(validator_context, field, str(config[field]))
+
+
+def _validate_secret_key(validator_context, field, secret_key):
+    remediation = REMEDIATIONS.get(field, REMEDIATION)
+
+    if secret_key.lower() in WEAK_SECRET_KEYS:
+        msg = "%s is a well-known placeholder value. %s" % (field, remediation)
+        raise ConfigValidationException(msg)
+
+    if _is_repeating(secret_key):
+        msg = "%s is a repeating sequence of characters. %s" % (field, remediation)
+        raise ConfigValidationException(msg)
+
+    # UUIDs are shorter than the minimum length, but are decoded specially by convert_secret_key
+    # and carry 122 random bits.
+    if _is_uuid(secret_key):
+        return
+
+    if len(secret_key) < MIN_SECRET_KEY_LENGTH:
+        msg = "%s is %s characters long; at least %s are required. %s" % (
+            field,
+            len(secret_key),
+            MIN_SECRET_KEY_LENGTH,
+            remediation,
+        )
+        raise ConfigValidationException(msg)
+
+    entropy_bits = _entropy_bits(secret_key)
+    if entropy_bits < MIN_SECRET_KEY_ENTROPY_BITS:
+        msg = "%s has an estimated entropy of %d bits; at least %s are required. %s" % (
+            field,
+            entropy_bits,
+            MIN_SECRET_KEY_ENTROPY_BITS,
+            remediation,
+        )
+        raise ConfigValidationException(msg)
+
+    if entropy_bits < RECOMMENDED_SECRET_KEY_ENTROPY_BITS:
+        msg = "%s has an estimated entropy of %d bits; at least %s are recommended. %s" % (
+            field,
+            entropy_bits,
+            RECOMMENDED_SECRET_KEY_ENTROPY_BITS,
+            remediation,
+        )
+        validator_context.add_warning(msg)
+
+
+def _is_repeating(value):
+    """
+    Returns whether the value consists of a shorter sequence repeated, such as `abcabcabc`.
+    """
+    return len(value) > 1 and (value + value).find(value, 1) < len(value)
+
+
+def _is_uuid(value):
+    try:
+        uuid.UUID(value)
+    except ValueError:
+        return False
+
+    return True
+
+
+def _entropy_bits(value):
+    """
+    Estimates the entropy of the value as its length times the Shannon entropy of its characters.
+    """
+    counts = Counter(value)
+    per_char = -sum(
+        (count / len(value)) * math.log2(count / len(value)) for count in counts.values()
+    )
+    return per_char * len(value)
@@ -2,7 +2,16 @@ import time
 
 # from boot import setup_jwt_proxy
 from util.secscan.api import SecurityScannerAPI
-from util.config.validators import BaseValidator, ConfigValidationException
+from util.secscan.v4.api import (
+    APIRequestFailure,
+    BadRequestResponseException,
+    ClairSecurityScannerAPI,
+    Non200ResponseException,
+)
+from util.config.validators import BaseValidator, ConfigValidationException, validate_base64
+
+# The recommended minimum length, in bytes, of the decoded V4 PSK, which is used as an HS256 key.
+MIN_V4_PSK_BYTES = 32
 
 
 class SecurityScannerValidator(BaseValidator):
@@ -24,6 +33,13 @@ class SecurityScannerValidator(BaseValidator):
         if not feature_sec_scanner:
             return
 
+        if config.get("SECURITY_SCANNER_V4_ENDPOINT"):
+            _validate_v4(validator_context)
+
+            # Skip the V2 checks if only the V4 security scanner is configured.
+            if not config.get("SECURITY_SCANNER_ENDPOINT"):
+                return
+
         api = SecurityScannerAPI(
             config,
             None,
@@ -60,3 +76,43 @@ class SecurityScannerValidator(BaseValidator):
         else:
             message = "Expected 200 status code, got %s: %s" % (response.status_code, response.text)
             raise ConfigValidationException("Could not ping security scanner: %s" % message)
+
+
+def _validate_v4(validator_context):
+    """
+    Validates the PSK for the V4 security scanner, and that the scanner answers an index state
+    request signed with it.
+    """
+    config = validator_context.config
+    endpoint = config["SECURITY_SCANNER_V4_ENDPOINT"]
+    psk = config.get("SECURITY_SCANNER_V4_PSK")
+
+    if psk is not None:
+        decoded_psk = validate_base64("SECURITY_SCANNER_V4_PSK", psk)
+        if len(decoded_psk) < MIN_V4_PSK_BYTES:
+            msg = "SECURITY_SCANNER_V4_PSK decodes to %s bytes; at least %s bytes are recommended"
+            validator_context.add_warning(msg % (len(decoded_psk), MIN_V4_PSK_BYTES))
+
+    api = ClairSecurityScannerAPI(endpoint, validator_context.http_client, None, jwt_psk=psk)
+    try:
+        api.state()
+        return
+    except BadRequestResponseException as brre:
+        status_code = brre.response.status_code
+    except APIRequestFailure as arf:
+        inner = arf.args[0] if arf.args else None
+        if not isinstance(inner, Non200ResponseException):
+            msg = "Could not validate V4 security scanner at %s: %s" % (endpoint, arf)
+            raise ConfigValidationException(msg)
+
+        status_code = inner.response.status_code
+
+    if status_code in (401, 403):
+        msg = (
+            "V4 security scanner at %s rejected the request with HTTP %s; check that "
+            + "SECURITY_SCANNER_V4_PSK matches the PSK configured in Clair"
+        )
+    else:
+        msg = "V4 security scanner at %s responded to the index state request with HTTP %s"
+
+    raise ConfigValidationException(msg % (endpoint, status_code))
@@ -1,7 +1,63 @@
-from util.config.validators import BaseValidator, ConfigValidationException
-from util.security.ssl import load_certificate, CertInvalidException, KeyInvalidException
+import ipaddress
+import os
+import re
+
+import certifi
+
+from OpenSSL import crypto, SSL
+
+from datetime import datetime, timedelta
+
+from util.config.validators import (
+    BaseValidator,
+    ConfigValidationException,
+    validate_text_encoding,
+)
+from util.security.ssl import (
+    load_certificate,
+    load_certificates,
+    load_private_key,
+    split_pem_blocks,
+    trailing_pem_data,
+    CertInvalidException,
+    KeyInvalidException,
+)
 
 SSL_FILENAMES = ["ssl.cert", "ssl.key"]
+EXTRA_CA_DIRECTORY = "extra_ca_certs"
+EXTRA_CA_DIRECTORY_PREFIX = "extra_ca_certs_"
+
+# The SSL protocols understood by nginx, oldest first, and the oldest which may be enabled.
+SSL_PROTOCOLS = ["SSLv2", "SSLv3", "TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"]
+MIN_SSL_PROTOCOL = "TLSv1.2"
+
+# The TLSv1.3 cipher suites, which OpenSSL configures separately from its cipher list.
+TLS13_CIPHER_SUITES = [
+    "TLS_AES_128_GCM_SHA256",
+    "TLS_AES_256_GCM_SHA384",
+    "TLS_CHACHA20_POLY1305_SHA256",
+    "TLS_AES_128_CCM_SHA256",
+    "TLS_AES_128_CCM_8_SHA256",
+]
+
+# Components of an OpenSSL cipher name or keyword which mark the cipher as insecure.
+INSECURE_CIPHER_COMPONENTS = set(
+    ["RC4", "DES", "3DES", "CBC3", "NULL", "ANULL", "ENULL", "EXP", "EXPORT", "MD5"]
+)
+
+# The minimum size, in bits, of an RSA SSL private key. Keys of exactly the minimum size raise a
+# warning recommending the larger size.
+MIN_RSA_KEY_BITS = 2048
+RECOMMENDED_RSA_KEY_BITS = 4096
+
+# The minimum size, in bits, of the curve of an EC SSL private key.
+MIN_EC_KEY_BITS = 256
+
+# The number of days before its expiration at which a warning is raised for the SSL certificate.
+SSL_EXPIRY_WARNING_DAYS = 30
+
+# The maximum number of certificate names listed when the server hostname does not match.
+MAX_LISTED_SSL_NAMES = 10
 
 
 class SSLValidator(BaseValidator):
@@ -15,23 +71,64 @@ class SSLValidator(BaseValidator):
         config = validator_context.config
         config_provider = validator_context.config_provider
 
-        # Skip if non-SSL.
+        # Warn if non-SSL, as registry credentials and content are then sent unencrypted.
         if config.get("PREFERRED_URL_SCHEME", "http") != "https":
+            _warn_plain_http(validator_context)
             return
 
         # Skip if externally terminated.
         if config.get("EXTERNAL_TLS_TERMINATION", False) is True:
             return
 
-        # Verify that we have all the required SSL files.
+        # Verify the SSL protocols nginx is configured to use, if any.
+        if "SSL_PROTOCOLS" in config:
+            _validate_ssl_protocols(config["SSL_PROTOCOLS"])
+
+        # Verify the SSL ciphers nginx is configured to use, if any.
+        if "SSL_CIPHERS" in config:
+            _validate_ssl_ciphers(validator_context, config["SSL_CIPHERS"])
+
+        # Verify that we have all the required SSL files, reporting every missing file at once.
+        missing = [name for name in SSL_FILENAMES if not config_provider.volume_file_exists(name)]
+        if missing:
+            msg = (
+                "Missing required SSL file(s): %s. Provide them, or set EXTERNAL_TLS_TERMINATION "
+                + "if TLS is terminated before Quay"
+            )
+            raise ConfigValidationException(msg % ", ".join(missing))
+
+        # Verify that the SSL files are plain UTF-8, as pasted PEM data often picks up a byte order
+        # mark or another encoding along the way.
         for filename in SSL_FILENAMES:
-            if not config_provider.volume_file_exists(filename):
-                raise ConfigValidationException("Missing required SSL file: %s" % filename)
+            with config_provider.get_volume_file(filename, mode="rb") as f:
+                validate_text_encoding(filename, f.read())
 
         # Read the contents of the SSL certificate.
         with config_provider.get_volume_file(SSL_FILENAMES[0], mode="rb") as f:
             cert_contents = f.read()
 
+        # Verify that every PEM block in the certificate file (including any intermediates) is a
+        # certificate.
+        try:
+            pem_blocks = split_pem_blocks(cert_contents)
+        except CertInvalidException as cie:
+            raise ConfigValidationException("Could not load SSL certificate: %s" % cie)
+
+        chain = []
+        for block_number, (block_type, block_contents) in enumerate(pem_blocks, 1):
+            if block_type != "CERTIFICATE":
+                msg = "SSL certificate block %s is of type %s; expected a CERTIFICATE" % (
+                    block_number,
+                    block_type,
+                )
+                raise ConfigValidationException(msg)
+
+            try:
+                chain.append(load_certificate(block_contents))
+            except CertInvalidException as cie:
+                msg = "Could not load SSL certificate block %s: %s" % (block_number, cie)
+                raise ConfigValidationException(msg)
+
         # Validate the certificate.
         try:
             certificate = load_certificate(cert_contents)
@@ -42,14 +139,52 @@ class SSLValidator(BaseValidator):
         if certificate.expired:
             raise ConfigValidationException("The specified SSL certificate has expired.")
 
+        # Verify the certificate is already valid, as automation tooling can generate certificates
+        # with a start date in the future.
+        if certificate.not_yet_valid:
+            msg = "The specified SSL certificate is not valid until %s UTC."
+            raise ConfigValidationException(msg % certificate.not_before)
+
+        # Warn if the certificate is about to expire.
+        expires_in = certificate.not_after - datetime.utcnow()
+        if expires_in < timedelta(days=SSL_EXPIRY_WARNING_DAYS):
+            validator_context.add_warning(
+                "The specified SSL certificate expires in %s day(s), at %s UTC."
+                % (expires_in.days, certificate.not_after)
+            )
+
         # Verify the hostname matches the name in the certificate.
         if not certificate.matches_name(_ssl_cn(config["SERVER_HOSTNAME"])):
+            _check_wildcard_depth(certificate, _ssl_cn(config["SERVER_HOSTNAME"]))
+
+            supported_names = sorted(certificate.names) + sorted(
+                str(ip_address) for ip_address in certificate.ip_addresses
+            )
             msg = 'Supported names "%s" in SSL cert do not match server hostname "%s"' % (
-                ", ".join(list(certificate.names)),
+                _format_names(supported_names),
                 _ssl_cn(config["SERVER_HOSTNAME"]),
             )
             raise ConfigValidationException(msg)
 
+        # Warn if the certificate (along with any intermediates) does not chain to a trusted root,
+        # as clients will then refuse to connect without additional configuration. The extra CA
+        # certificates are only installed into the system trust store on startup, so they are
+        # trusted explicitly here.
+        extra_ca_certs = _extra_ca_certificates(validator_context)
+        try:
+            certificate.verify_chain(chain[1:], _system_trusted_certificates() + extra_ca_certs)
+        except CertInvalidException as cie:
+            validator_context.add_warning(
+                "SSL certificate (subject %s, issued by %s) could not be verified against the "
+                "system trust store%s: %s"
+                % (
+                    certificate.subject,
+                    certificate.issuer,
+                    " or the extra CA certificates" if extra_ca_certs else "",
+                    cie,
+                )
+            )
+
         # Verify the private key against the certificate.
         private_key_path = None
         with config_provider.get_volume_file(SSL_FILENAMES[1]) as f:
@@ -64,11 +199,225 @@ class SSLValidator(BaseValidator):
         except KeyInvalidException as kie:
             raise ConfigValidationException("SSL private key failed to validate: %s" % kie)
 
+        # Verify the private key's algorithm and size.
+        with config_provider.get_volume_file(SSL_FILENAMES[1], mode="rb") as f:
+            private_key = load_private_key(f.read())
+
+        if private_key.type() == crypto.TYPE_RSA:
+            if private_key.bits() < MIN_RSA_KEY_BITS:
+                msg = "SSL private key is a %s-bit RSA key; at least %s bits are required" % (
+                    private_key.bits(),
+                    MIN_RSA_KEY_BITS,
+                )
+                raise ConfigValidationException(msg)
+
+            if private_key.bits() == MIN_RSA_KEY_BITS:
+                validator_context.add_warning(
+                    "SSL private key is a %s-bit RSA key; %s bits are recommended"
+                    % (private_key.bits(), RECOMMENDED_RSA_KEY_BITS)
+                )
+        elif private_key.type() == crypto.TYPE_EC:
+            if private_key.bits() < MIN_EC_KEY_BITS:
+                curve_name = private_key.to_cryptography_key().curve.name
+                msg = "SSL private key is an EC key on the %s-bit %s curve; " % (
+                    private_key.bits(),
+                    curve_name,
+                )
+                msg += "a curve of at least %s bits is required" % MIN_EC_KEY_BITS
+                raise ConfigValidationException(msg)
+        else:
+            msg = "SSL private key must be an RSA or EC key"
+            raise ConfigValidationException(msg)
+
+
+def _validate_ssl_protocols(protocols):
+    """
+    Verifies that the given list of SSL protocols is non-empty, only names protocols known to nginx
+    and does not enable any protocol older than the minimum.
+    """
+    if not protocols:
+        raise ConfigValidationException("SSL_PROTOCOLS must enable at least one protocol")
+
+    unknown = [protocol for protocol in protocols if protocol not in SSL_PROTOCOLS]
+    if unknown:
+        msg = "Unknown protocol(s) in SSL_PROTOCOLS: %s. Expected one of: %s" % (
+            ", ".join(unknown),
+            ", ".join(SSL_PROTOCOLS),
+        )
+        raise ConfigValidationException(msg)
+
+    minimum_index = SSL_PROTOCOLS.index(MIN_SSL_PROTOCOL)
+    insecure = [p for p in protocols if SSL_PROTOCOLS.index(p) < minimum_index]
+    if insecure:
+        msg = "Insecure protocol(s) in SSL_PROTOCOLS: %s. %s or later is required" % (
+            ", ".join(insecure),
+            MIN_SSL_PROTOCOL,
+        )
+        raise ConfigValidationException(msg)
+
+
+def _validate_ssl_ciphers(validator_context, ciphers):
+    """
+    Verifies that every cipher enabled by the given nginx cipher list is known to OpenSSL, warning
+    for any that are insecure. Entries which disable or reorder ciphers are not checked.
+    """
+    unknown = []
+    insecure = []
+    for cipher in ciphers:
+        if cipher[:1] in ("!", "-", "+") or cipher in TLS13_CIPHER_SUITES:
+            continue
+
+        if INSECURE_CIPHER_COMPONENTS.intersection(re.split(r"[-+]", cipher.upper())):
+            insecure.append(cipher)
+            continue
+
+        try:
+            SSL.Context(SSL.SSLv23_METHOD).set_cipher_list(cipher.encode("ascii"))
+        except (SSL.Error, UnicodeEncodeError):
+            unknown.append(cipher)
+
+    if unknown:
+        msg = "Unknown cipher(s) in SSL_CIPHERS: %s" % ", ".join(unknown)
+        raise ConfigValidationException(msg)
+
+    if insecure:
+        validator_context.add_warning(
+            "Insecure cipher(s) enabled in SSL_CIPHERS: %s" % ", ".join(insecure)
+        )
+
+
+def _system_trusted_certificates():
+    """
+    Returns the certificates in the CA bundle used by Quay's HTTP clients, into which any extra CA
+    certificates are installed on startup.
+    """
+    with open(certifi.where(), "rb") as f:
+        return load_certificates(f.read())
+
+
+def _warn_plain_http(validator_context):
+    """
+    Adds a warning describing the consequences of a plain http PREFERRED_URL_SCHEME, which is only
+    expected in test setups.
+    """
+    config = validator_context.config
+    config_provider = validator_context.config_provider
+
+    if config.get("EXTERNAL_TLS_TERMINATION", False) is True:
+        msg = (
+            "EXTERNAL_TLS_TERMINATION is set, but PREFERRED_URL_SCHEME is http, so Quay generates "
+            + "http:// URLs. Set PREFERRED_URL_SCHEME to https"
+        )
+        validator_context.add_warning(msg)
+        return
+
+    # nginx serves TLS whenever the SSL files are present, regardless of the URL scheme.
+    if any(config_provider.volume_file_exists(filename) for filename in SSL_FILENAMES):
+        msg = (
+            "SSL certificate files are present, but PREFERRED_URL_SCHEME is http, so Quay "
+            + "generates http:// URLs. Set PREFERRED_URL_SCHEME to https"
+        )
+        validator_context.add_warning(msg)
+        return
+
+    msg = (
+        "PREFERRED_URL_SCHEME is http, so registry credentials and content are sent unencrypted. "
+        + "Use https outside of test setups"
+    )
+    validator_context.add_warning(msg)
+
+
+def _extra_ca_certificates(validator_context):
+    """
+    Returns the certificates found in the extra CA certificates directory of the config volume,
+    warning for any which cannot act as a CA.
+    """
+    config_provider = validator_context.config_provider
+
+    certificates = []
+    for filename in config_provider.list_volume_directory(EXTRA_CA_DIRECTORY) or []:
+        cert_path = os.path.join(EXTRA_CA_DIRECTORY, filename)
+        with config_provider.get_volume_file(cert_path, mode="rb") as f:
+            contents = f.read()
+
+        validate_text_encoding(cert_path, contents)
+        try:
+            file_certificates = load_certificates(contents)
+        except CertInvalidException as cie:
+            msg = "Could not load extra CA certificate %s: %s" % (filename, cie)
+            raise ConfigValidationException(msg)
+
+        # Anything after the last certificate is most likely a truncated or mangled certificate,
+        # which would otherwise be silently ignored.
+        if trailing_pem_data(contents):
+            if not file_certificates:
+                msg = "Extra CA certificate %s does not contain any PEM certificates"
+                raise ConfigValidationException(msg % filename)
+
+            msg = "Extra CA certificate %s has unexpected data after its last PEM certificate"
+            raise ConfigValidationException(msg % filename)
+
+        # A leaf certificate pasted in place of its CA is only trusted as itself, so it does not
+        # verify anything else. This is not an error, as it is also how a self-signed server
+        # certificate is trusted.
+        for certificate in file_certificates:
+            if not certificate.is_ca:
+                msg = "Extra CA certificate %s (subject %s) is not a CA certificate, so it "
+                msg += "cannot be used to verify other certificates"
+                validator_context.add_warning(msg % (filename, certificate.subject))
+            elif not certificate.can_sign_certificates:
+                msg = "Extra CA certificate %s (subject %s) is a CA, but its key usage does not "
+                msg += "permit signing certificates"
+                validator_context.add_warning(msg % (filename, certificate.subject))
+
+        certificates.extend(file_certificates)
+
+    return certificates
+
+
+def _check_wildcard_depth(certificate, hostname):
+    """
+    Raises a ConfigValidationException explaining wildcard scope if the hostname is a deeper
+    subdomain of one of the certificate's wildcard names, as a wildcard only matches a single label.
+    """
+    for name in sorted(certificate.names):
+        if name.startswith("*.") and hostname.lower().endswith(name[1:].lower()):
+            msg = (
+                'Wildcard name "%s" in SSL cert only covers a single level of subdomain, so it '
+                + 'does not match server hostname "%s"'
+            )
+            raise ConfigValidationException(msg % (name, hostname))
+
+
+def _format_names(names):
+    """
+    Formats the given certificate names for an error message, truncating long lists with a count of
+    the names left out.
+    """
+    if len(names) <= MAX_LISTED_SSL_NAMES:
+        return ", ".join(names)
+
+    return "%s and %s more" % (
+        ", ".join(names[:MAX_LISTED_SSL_NAMES]),
+        len(names) - MAX_LISTED_SSL_NAMES,
+    )
+
 
 def _ssl_cn(server_hostname):
     """
     Return the common name (fully qualified host name) from the SERVER_HOSTNAME.
     """
+    # SERVER_HOSTNAME is a bracketed IPv6 address, with or without a port.
+    if server_hostname.startswith("[") and "]" in server_hostname:
+        return server_hostname[1:].split("]", 1)[0]
+
+    # SERVER_HOSTNAME is a bare IP address, which may itself contain colons.
+    try:
+        ipaddress.ip_address(server_hostname)
+        return server_hostname
+    except ValueError:
+        pass
+
     host_port = server_hostname.rsplit(":", 1)
 
     # SERVER_HOSTNAME includes the port
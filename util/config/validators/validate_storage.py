@@ -1,6 +1,15 @@
 from storage import get_storage_driver, TYPE_LOCAL_STORAGE
 from util.config.validators import BaseValidator, ConfigValidationException
 
+# The number of storage locations above which a warning is raised, as each additional location adds
+# to the work done by the replication workers.
+MAX_STORAGE_LOCATIONS = 10
+
+# The storage driver parameters which name the bucket (or container) holding a location's data,
+# and those which name the server or account hosting it.
+_BUCKET_PARAMETERS = ["s3_bucket", "bucket_name", "azure_container", "swift_container"]
+_HOST_PARAMETERS = ["hostname", "azure_account_name", "auth_url"]
+
 
 class StorageValidator(BaseValidator):
     name = "registry-storage"
@@ -21,6 +30,15 @@ class StorageValidator(BaseValidator):
         if not providers:
             raise ConfigValidationException("Storage configuration required")
 
+        if len(providers) > MAX_STORAGE_LOCATIONS:
+            validator_context.add_warning(
+                "%s storage locations are configured; more than %s can overwhelm replication"
+                % (len(providers), MAX_STORAGE_LOCATIONS)
+            )
+
+        _validate_location_references(validator_context)
+        _validate_unique_buckets(validator_context)
+
         for name, (storage_type, driver) in providers:
             # We can skip localstorage validation, since we can't guarantee that
             # this will be the same machine Q.E. will run under
@@ -33,8 +51,10 @@ class StorageValidator(BaseValidator):
                         "Locally mounted directory not supported " + "with storage replication"
                     )
 
-                # Run validation on the driver.
+                # Run validation on the driver, and then remove its verification file so that
+                # validation leaves the storage as it found it.
                 driver.validate(client)
+                driver.remove("_verify")
 
                 # Run setup on the driver if the read/write succeeded.
                 driver.setup()
@@ -57,3 +77,56 @@ def _get_storage_providers(config, ip_resolver, config_provider):
         raise ConfigValidationException("Missing required parameter(s) for storage %s" % name)
 
     return drivers
+
+
+def _validate_location_references(validator_context):
+    """
+    Verifies that the storage location routing settings only reference locations defined in the
+    storage config.
+    """
+    config = validator_context.config
+    storage_config = config.get("DISTRIBUTED_STORAGE_CONFIG", {})
+
+    for key in ["DISTRIBUTED_STORAGE_PREFERENCE", "DISTRIBUTED_STORAGE_DEFAULT_LOCATIONS"]:
+        locations = config.get(key) or []
+
+        undefined = [location for location in locations if location not in storage_config]
+        if undefined:
+            msg = "Storage location(s) in %s not found in storage config: %s" % (
+                key,
+                ", ".join(undefined),
+            )
+            raise ConfigValidationException(msg)
+
+        duplicates = sorted(
+            set([location for location in locations if locations.count(location) > 1])
+        )
+        if duplicates:
+            validator_context.add_warning(
+                "Duplicate storage location(s) in %s: %s" % (key, ", ".join(duplicates))
+            )
+
+
+def _validate_unique_buckets(validator_context):
+    """
+    Warns when two storage locations store their data under the same bucket and path, as they
+    would then overwrite each other's data.
+    """
+    storage_config = validator_context.config.get("DISTRIBUTED_STORAGE_CONFIG", {})
+
+    locations_by_bucket = {}
+    for name, (_, parameters) in sorted(storage_config.items()):
+        bucket = next((parameters[p] for p in _BUCKET_PARAMETERS if parameters.get(p)), None)
+        if bucket is None:
+            continue
+
+        host = next((parameters[p] for p in _HOST_PARAMETERS if parameters.get(p)), None)
+        path = (parameters.get("storage_path") or "").strip("/")
+        locations_by_bucket.setdefault((host, bucket, path), []).append(name)
+
+    for (_, bucket, path), names in sorted(locations_by_bucket.items(), key=lambda i: i[1]):
+        if len(names) > 1:
+            validator_context.add_warning(
+                "Storage locations %s share bucket `%s` and path `/%s`"
+                % (", ".join(names), bucket, path)
+            )
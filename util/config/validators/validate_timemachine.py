@@ -1,7 +1,7 @@
 import logging
 
 from util.config.validators import BaseValidator, ConfigValidationException
-from util.timedeltastring import convert_to_timedelta
+from util.timedeltastring import convert_to_timedelta, DURATION_FORMAT_DESCRIPTION
 
 logger = logging.getLogger(__name__)
 
@@ -19,8 +19,12 @@ class TimeMachineValidator(BaseValidator):
 
         try:
             convert_to_timedelta(config["DEFAULT_TAG_EXPIRATION"]).total_seconds()
-        except ValueError as ve:
-            raise ConfigValidationException("Invalid default expiration: %s" % str(ve))
+        except ValueError:
+            msg = "Invalid default expiration `%s`: expected %s" % (
+                config["DEFAULT_TAG_EXPIRATION"],
+                DURATION_FORMAT_DESCRIPTION,
+            )
+            raise ConfigValidationException(msg)
 
         if not config["DEFAULT_TAG_EXPIRATION"] in config.get("TAG_EXPIRATION_OPTIONS", []):
             raise ConfigValidationException("Default expiration must be in expiration options set")
@@ -28,5 +32,9 @@ class TimeMachineValidator(BaseValidator):
         for ts in config.get("TAG_EXPIRATION_OPTIONS", []):
             try:
                 convert_to_timedelta(ts)
-            except ValueError as ve:
-                raise ConfigValidationException("Invalid tag expiration option: %s" % ts)
+            except ValueError:
+                msg = "Invalid tag expiration option `%s`: expected %s" % (
+                    ts,
+                    DURATION_FORMAT_DESCRIPTION,
+                )
+                raise ConfigValidationException(msg)
@@ -1,3 +1,9 @@
+import base64
+import binascii
+import ipaddress
+import re
+
+from datetime import datetime
 from fnmatch import fnmatch
 
 import OpenSSL
@@ -31,7 +37,125 @@ def load_certificate(cert_contents):
         raise CertInvalidException(ex.args[0][0][2])
 
 
+def load_private_key(key_contents):
+    """
+    Loads the private key from the given contents and returns it or raises a KeyInvalidException on
+    failure.
+    """
+    try:
+        return OpenSSL.crypto.load_privatekey(OpenSSL.crypto.FILETYPE_PEM, key_contents)
+    except OpenSSL.crypto.Error as ex:
+        raise KeyInvalidException(ex.args[0][0][2])
+
+
+_PEM_BEGIN = b"-----BEGIN "
+_PEM_BLOCK_REGEX = re.compile(rb"-----BEGIN ([A-Z0-9 ]+)-----(.*?)-----END \1-----", re.DOTALL)
+
+
+def split_pem_blocks(contents):
+    """
+    Splits the given PEM contents into its blocks and returns a list of (block type, block contents)
+    tuples, in order. Raises a CertInvalidException naming the (1-based) block number if any block
+    could not be decoded.
+    """
+    if isinstance(contents, str):
+        contents = contents.encode("utf-8")
+
+    blocks = []
+    position = contents.find(_PEM_BEGIN)
+    while position >= 0:
+        block_number = len(blocks) + 1
+        match = _PEM_BLOCK_REGEX.match(contents, position)
+        if match is None:
+            raise CertInvalidException("PEM block %s is missing its END line" % block_number)
+
+        # Skip any RFC 1421 headers (such as `Proc-Type`) before decoding the body.
+        body_lines = [line.strip() for line in match.group(2).splitlines() if b":" not in line]
+        try:
+            base64.b64decode(b"".join(body_lines), validate=True)
+        except binascii.Error:
+            raise CertInvalidException("PEM block %s could not be decoded" % block_number)
+
+        blocks.append((match.group(1).decode("ascii"), match.group(0)))
+        position = contents.find(_PEM_BEGIN, match.end())
+
+    return blocks
+
+
+def trailing_pem_data(contents):
+    """
+    Returns any data, other than whitespace, following the last PEM block in the given contents, or
+    all of the contents if there are no PEM blocks.
+    """
+    if isinstance(contents, str):
+        contents = contents.encode("utf-8")
+
+    end = 0
+    for match in _PEM_BLOCK_REGEX.finditer(contents):
+        end = match.end()
+
+    return contents[end:].strip()
+
+
+def load_certificates(contents):
+    """
+    Loads every certificate found in the given PEM bundle and returns them in order, or raises a
+    CertInvalidException on failure.
+    """
+    certificates = []
+    for block_number, (block_type, block_contents) in enumerate(split_pem_blocks(contents), 1):
+        if block_type != "CERTIFICATE":
+            msg = "PEM block %s is of type %s; expected a CERTIFICATE" % (block_number, block_type)
+            raise CertInvalidException(msg)
+
+        certificates.append(load_certificate(block_contents))
+
+    return certificates
+
+
 _SUBJECT_ALT_NAME = b"subjectAltName"
+_BASIC_CONSTRAINTS = b"basicConstraints"
+_KEY_USAGE = b"keyUsage"
+_ASN1_TIME_FORMAT = "%Y%m%d%H%M%SZ"
+
+
+def _verify_against_store(certificate, store):
+    try:
+        OpenSSL.crypto.X509StoreContext(store, certificate.openssl_cert).verify_certificate()
+        return None
+    except OpenSSL.crypto.X509StoreContextError as ex:
+        return ex.args[0][2]
+
+
+def _format_x509_name(name):
+    return "".join(
+        "/%s=%s" % (key.decode("utf-8"), value.decode("utf-8"))
+        for key, value in name.get_components()
+    )
+
+
+def _parse_ip_address(value):
+    try:
+        return ipaddress.ip_address(value)
+    except ValueError:
+        return None
+
+
+def _matches_dns_name(check_name, dns_name):
+    """
+    Returns whether the given hostname matches the DNS name from a certificate. As in RFC 6125, a
+    wildcard may only appear in the leftmost label, and matches exactly one label.
+    """
+    check_labels = check_name.lower().split(".")
+    dns_labels = dns_name.lower().split(".")
+    if len(check_labels) != len(dns_labels):
+        return False
+
+    return fnmatch(check_labels[0], dns_labels[0]) and check_labels[1:] == dns_labels[1:]
+
+
+def _parse_asn1_time(value):
+    return datetime.strptime(value.decode("ascii"), _ASN1_TIME_FORMAT)
 
 
 class SSLCertificate(object):
@@ -57,12 +181,43 @@ class SSLCertificate(object):
         except OpenSSL.SSL.Error as ex:
             raise KeyInvalidException(ex.args[0][0][2])
 
+    def verify_chain(self, intermediate_certs, trusted_certs):
+        """
+        Verifies that this certificate chains, via the given intermediate certificates, to one of
+        the given trusted certificates.
+
+        Raises a CertInvalidException on failure.
+        """
+        store = OpenSSL.crypto.X509Store()
+        for trusted_cert in trusted_certs:
+            store.add_cert(trusted_cert.openssl_cert)
+
+        # Only add an intermediate to the store once it has itself been verified, as anything in
+        # the store is treated as trusted.
+        remaining = list(intermediate_certs)
+        while remaining:
+            verified = [cert for cert in remaining if _verify_against_store(cert, store) is None]
+            if not verified:
+                break
+
+            for cert in verified:
+                store.add_cert(cert.openssl_cert)
+                remaining.remove(cert)
+
+        error = _verify_against_store(self, store)
+        if error is not None:
+            raise CertInvalidException(error)
+
     def matches_name(self, check_name):
         """
-        Returns true if this SSL certificate matches the given DNS hostname.
+        Returns true if this SSL certificate matches the given DNS hostname or IP address.
         """
+        check_ip = _parse_ip_address(check_name)
+        if check_ip is not None and check_ip in self.ip_addresses:
+            return True
+
         for dns_name in self.names:
-            if fnmatch(check_name, dns_name):
+            if _matches_dns_name(check_name, dns_name):
                 return True
 
         return False
@@ -74,6 +229,41 @@ class SSLCertificate(object):
         """
         return self.openssl_cert.has_expired()
 
+    @property
+    def not_before(self):
+        """
+        Returns the (UTC) datetime at which the SSL certificate becomes valid.
+        """
+        return _parse_asn1_time(self.openssl_cert.get_notBefore())
+
+    @property
+    def not_after(self):
+        """
+        Returns the (UTC) datetime at which the SSL certificate expires.
+        """
+        return _parse_asn1_time(self.openssl_cert.get_notAfter())
+
+    @property
+    def not_yet_valid(self):
+        """
+        Returns whether the SSL certificate's validity period has yet to begin.
+        """
+        return self.not_before > datetime.utcnow()
+
+    @property
+    def subject(self):
+        """
+        Returns the certificate's subject, formatted as a string.
+        """
+        return _format_x509_name(self.openssl_cert.get_subject())
+
+    @property
+    def issuer(self):
+        """
+        Returns the certificate's issuer, formatted as a string.
+        """
+        return _format_x509_name(self.openssl_cert.get_issuer())
+
     @property
     def common_name(self):
         """
@@ -94,13 +284,70 @@ class SSLCertificate(object):
             dns_names.add(common_name)
 
         # Find the DNS extension, if any.
+        for san_name in self.subject_alt_names:
+            if san_name.startswith("DNS:"):
+                dns_names.add(san_name[4:])
+
+        return dns_names
+
+    @property
+    def ip_addresses(self):
+        """
+        Returns all the IP addresses to which the certificate applies.
+
+        May be empty.
+        """
+        ip_addresses = set()
+        for san_name in self.subject_alt_names:
+            if san_name.startswith("IP Address:"):
+                ip_address = _parse_ip_address(san_name[len("IP Address:") :])
+                if ip_address is not None:
+                    ip_addresses.add(ip_address)
+
+        return ip_addresses
+
+    @property
+    def is_ca(self):
+        """
+        Returns whether the certificate's basic constraints mark it as a certificate authority.
+        """
+        basic_constraints = self._extension_value(_BASIC_CONSTRAINTS)
+        return basic_constraints is not None and "CA:TRUE" in basic_constraints
+
+    @property
+    def can_sign_certificates(self):
+        """
+        Returns whether the certificate's key usage, if restricted, permits signing certificates.
+        """
+        key_usage = self._extension_value(_KEY_USAGE)
+        return key_usage is None or "Certificate Sign" in key_usage
+
+    def _extension_value(self, short_name):
+        """
+        Returns the value of the extension with the given short name, formatted as a string, or None
+        if the certificate does not have it.
+        """
+        for i in range(0, self.openssl_cert.get_extension_count()):
+            ext = self.openssl_cert.get_extension(i)
+            if ext.get_short_name() == short_name:
+                return str(ext)
+
+        return None
+
+    @property
+    def subject_alt_names(self):
+        """
+        Returns all the subject alternative names in the certificate, each prefixed with its type
+        (for example `DNS:` or `IP Address:`).
+
+        May be empty.
+        """
+        san_names = []
         for i in range(0, self.openssl_cert.get_extension_count()):
             ext = self.openssl_cert.get_extension(i)
             if ext.get_short_name() == _SUBJECT_ALT_NAME:
                 value = str(ext)
                 for san_name in value.split(","):
-                    san_name_trimmed = san_name.strip()
-                    if san_name_trimmed.startswith("DNS:"):
-                        dns_names.add(san_name_trimmed[4:])
+                    san_names.append(san_name.strip())
 
-        return dns_names
+        return san_names
@@ -2,20 +2,46 @@ from tempfile import NamedTemporaryFile
 
 import pytest
 
+from cryptography.hazmat.backends import default_backend
+from cryptography.hazmat.primitives.asymmetric import ec
 from OpenSSL import crypto
 
-from util.security.ssl import load_certificate, CertInvalidException, KeyInvalidException
-
-
-def generate_test_cert(hostname="somehostname", san_list=None, expires=1000000):
+from util.security.ssl import (
+    load_certificate,
+    load_certificates,
+    split_pem_blocks,
+    trailing_pem_data,
+    CertInvalidException,
+    KeyInvalidException,
+)
+
+
+def generate_test_cert(
+    hostname="somehostname",
+    san_list=None,
+    expires=1000000,
+    not_before=0,
+    issuer=None,
+    is_ca=False,
+    key_size=2048,
+    ec_curve=None,
+    key_usage=None,
+):
     """
-    Generates a test SSL certificate and returns the certificate data and private key data.
+    Generates a test SSL certificate and returns the certificate data and private key data. If an
+    issuer (certificate data, private key data) pair is given, the certificate is signed by it
+    instead of being self-signed. If an EC curve is given, an EC key is generated on that curve in
+    place of an RSA key. If a key usage (such as b"digitalSignature") is given, it is added as a
+    critical extension.
     """
 
     # Based on: http://blog.richardknop.com/2012/08/create-a-self-signed-x509-certificate-in-python/
     # Create a key pair.
-    k = crypto.PKey()
-    k.generate_key(crypto.TYPE_RSA, 1024)
+    if ec_curve is not None:
+        k = crypto.PKey.from_cryptography_key(ec.generate_private_key(ec_curve, default_backend()))
+    else:
+        k = crypto.PKey()
+        k.generate_key(crypto.TYPE_RSA, key_size)
 
     # Create a self-signed cert.
     cert = crypto.X509()
@@ -26,12 +52,26 @@ def generate_test_cert(hostname="somehostname", san_list=None, expires=1000000):
         cert.add_extensions([crypto.X509Extension(b"subjectAltName", False, b", ".join(san_list))])
 
     cert.set_serial_number(1000)
-    cert.gmtime_adj_notBefore(0)
+    cert.gmtime_adj_notBefore(not_before)
     cert.gmtime_adj_notAfter(expires)
-    cert.set_issuer(cert.get_subject())
+
+    if is_ca:
+        cert.set_version(2)
+        cert.add_extensions([crypto.X509Extension(b"basicConstraints", True, b"CA:TRUE")])
+
+    if key_usage is not None:
+        cert.add_extensions([crypto.X509Extension(b"keyUsage", True, key_usage)])
 
     cert.set_pubkey(k)
-    cert.sign(k, "sha1")
+
+    if issuer is not None:
+        issuer_cert = crypto.load_certificate(crypto.FILETYPE_PEM, issuer[0])
+        issuer_key = crypto.load_privatekey(crypto.FILETYPE_PEM, issuer[1])
+        cert.set_issuer(issuer_cert.get_subject())
+        cert.sign(issuer_key, "sha256")
+    else:
+        cert.set_issuer(cert.get_subject())
+        cert.sign(k, "sha256")
 
     # Dump the certificate and private key in PEM format.
     cert_data = crypto.dump_certificate(crypto.FILETYPE_PEM, cert)
@@ -61,6 +101,56 @@ def test_expired_certificate():
     assert cert.expired
 
 
+def test_not_yet_valid_certificate():
+    (public_key_data, _) = generate_test_cert(not_before=100000, expires=200000)
+
+    cert = load_certificate(public_key_data)
+    assert cert.not_yet_valid
+    assert not cert.expired
+    assert (cert.not_after - cert.not_before).total_seconds() == 100000
+
+
+def test_verify_chain():
+    root = generate_test_cert(hostname="someroot", is_ca=True)
+    intermediate = generate_test_cert(hostname="someintermediate", issuer=root, is_ca=True)
+    (public_key_data, _) = generate_test_cert(issuer=intermediate)
+
+    [cert, intermediate_cert, root_cert] = load_certificates(
+        public_key_data + intermediate[0] + root[0]
+    )
+
+    cert.verify_chain([intermediate_cert], [root_cert])
+
+    # Missing intermediate.
+    with pytest.raises(CertInvalidException):
+        cert.verify_chain([], [root_cert])
+
+    # Untrusted root.
+    with pytest.raises(CertInvalidException):
+        cert.verify_chain([intermediate_cert, root_cert], [])
+
+    # Self-signed.
+    with pytest.raises(CertInvalidException):
+        root_cert.verify_chain([], [])
+
+
+def test_certificate_authority():
+    (ca_data, _) = generate_test_cert(is_ca=True, key_usage=b"keyCertSign, cRLSign")
+    ca_cert = load_certificate(ca_data)
+    assert ca_cert.is_ca
+    assert ca_cert.can_sign_certificates
+
+    (leaf_data, _) = generate_test_cert()
+    leaf_cert = load_certificate(leaf_data)
+    assert not leaf_cert.is_ca
+    assert leaf_cert.can_sign_certificates
+
+    (no_sign_data, _) = generate_test_cert(is_ca=True, key_usage=b"digitalSignature")
+    no_sign_cert = load_certificate(no_sign_data)
+    assert no_sign_cert.is_ca
+    assert not no_sign_cert.can_sign_certificates
+
+
 def test_hostnames():
     (public_key_data, _) = generate_test_cert(hostname="foo", san_list=[b"DNS:bar", b"DNS:baz"])
     cert = load_certificate(public_key_data)
@@ -81,7 +171,25 @@ def test_wildcard_hostnames():
     assert cert.matches_name("something.bar")
     assert cert.matches_name("somethingelse.bar")
     assert cert.matches_name("cool.bar")
+    assert cert.matches_name("COOL.Bar")
     assert not cert.matches_name("*")
+    assert not cert.matches_name("bar")
+
+    # A wildcard only matches a single label.
+    assert not cert.matches_name("very.cool.bar")
+
+
+def test_ip_address_hostnames():
+    (public_key_data, _) = generate_test_cert(
+        hostname="foo", san_list=[b"DNS:bar", b"IP:10.0.0.1", b"IP:2001:db8::1"]
+    )
+    cert = load_certificate(public_key_data)
+    assert cert.names == set(["foo", "bar"])
+    assert set(str(ip) for ip in cert.ip_addresses) == set(["10.0.0.1", "2001:db8::1"])
+
+    assert cert.matches_name("10.0.0.1")
+    assert cert.matches_name("2001:db8:0::1")
+    assert not cert.matches_name("10.0.0.2")
 
 
 def test_nondns_hostnames():
@@ -124,3 +232,45 @@ def test_mismatch_private_key():
     cert = load_certificate(public_key_data)
     with pytest.raises(KeyInvalidException):
         cert.validate_private_key(private_key.name)
+
+
+def test_split_pem_blocks():
+    (first_cert, first_key) = generate_test_cert(hostname="first")
+    (second_cert, _) = generate_test_cert(hostname="second")
+
+    blocks = split_pem_blocks(first_cert + second_cert + first_key)
+    assert [block_type for (block_type, _) in blocks][:2] == ["CERTIFICATE", "CERTIFICATE"]
+    assert blocks[2][0].endswith("PRIVATE KEY")
+    assert load_certificate(blocks[1][1]).common_name == "second"
+
+    assert split_pem_blocks(b"not a pem file") == []
+
+
+@pytest.mark.parametrize(
+    "second_block, error_message",
+    [
+        (
+            b"-----BEGIN CERTIFICATE-----\nnot!base64\n-----END CERTIFICATE-----\n",
+            "PEM block 2 could not be decoded",
+        ),
+        (b"-----BEGIN CERTIFICATE-----\nAAAA\n", "PEM block 2 is missing its END line"),
+    ],
+)
+def test_split_invalid_pem_blocks(second_block, error_message):
+    (first_cert, _) = generate_test_cert()
+
+    with pytest.raises(CertInvalidException) as cie:
+        split_pem_blocks(first_cert + second_block)
+
+    assert str(cie.value) == error_message
+
+
+def test_trailing_pem_data():
+    (cert_data, _) = generate_test_cert()
+
+    assert trailing_pem_data(cert_data) == b""
+    assert trailing_pem_data(b"some comment\n" + cert_data + b"\n\n") == b""
+    assert trailing_pem_data(cert_data + b"MIIBIjANBgkqhkiG9w0BAQEFAAOC\n") == (
+        b"MIIBIjANBgkqhkiG9w0BAQEFAAOC"
+    )
+    assert trailing_pem_data(b"not a certificate") == b"not a certificate"
@@ -0,0 +1,40 @@
+import pytest
+
+from datetime import timedelta
+
+from util.timedeltastring import convert_to_timedelta
+
+
+@pytest.mark.parametrize(
+    "time_val, expected",
+    [
+        ("120s", timedelta(seconds=120)),
+        ("90m", timedelta(minutes=90)),
+        ("24h", timedelta(hours=24)),
+        ("7d", timedelta(days=7)),
+        ("2w", timedelta(weeks=2)),
+        ("1h30m", timedelta(hours=1, minutes=30)),
+        ("1w2d3h", timedelta(weeks=1, days=2, hours=3)),
+    ],
+)
+def test_convert_to_timedelta(time_val, expected):
+    assert convert_to_timedelta(time_val) == expected
+
+
+@pytest.mark.parametrize(
+    "time_val",
+    [
+        "",
+        "5",
+        "1M",
+        "1y",
+        "h",
+        "1h 30m",
+        "-1h",
+    ],
+)
+def test_convert_invalid_timedelta(time_val):
+    with pytest.raises(ValueError) as ve:
+        convert_to_timedelta(time_val)
+
+    assert "such as 30m, 2w or 1h30m" in str(ve.value)
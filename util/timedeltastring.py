@@ -1,5 +1,24 @@
+import re
+
 from datetime import timedelta
 
+_UNITS = {
+    "s": "seconds",
+    "m": "minutes",
+    "h": "hours",
+    "d": "days",
+    "w": "weeks",
+}
+
+_DURATION_REGEX = re.compile(r"^([0-9]+[smhdw])+$")
+_COMPONENT_REGEX = re.compile(r"([0-9]+)([smhdw])")
+
+# A human readable description of the accepted duration format, for use in error messages.
+DURATION_FORMAT_DESCRIPTION = (
+    "a number followed by a unit (s for seconds, m for minutes, h for hours, d for days or w for "
+    + "weeks), such as 30m, 2w or 1h30m"
+)
+
 
 def convert_to_timedelta(time_val):
     """
@@ -19,6 +38,9 @@ def convert_to_timedelta(time_val):
     w           Weeks   '2w'  -> 2 weeks
     =========   ======= ===================
 
+    Note that `m` is always minutes; months are not supported. Formats may be combined to express
+    composite durations such as '1h30m'.
+
     Examples::
 
         >>> convert_to_timedelta('7d')
@@ -29,17 +51,15 @@ def convert_to_timedelta(time_val):
         datetime.timedelta(0, 3600)
         >>> convert_to_timedelta('120s')
         datetime.timedelta(0, 120)
+        >>> convert_to_timedelta('1h30m')
+        datetime.timedelta(0, 5400)
     """
-    num = int(time_val[:-1])
-    if time_val.endswith("s"):
-        return timedelta(seconds=num)
-    elif time_val.endswith("m"):
-        return timedelta(minutes=num)
-    elif time_val.endswith("h"):
-        return timedelta(hours=num)
-    elif time_val.endswith("d"):
-        return timedelta(days=num)
-    elif time_val.endswith("w"):
-        return timedelta(days=num * 7)
-    else:
-        raise ValueError("Unknown suffix on timedelta: %s" % time_val)
+    if not _DURATION_REGEX.match(time_val or ""):
+        msg = "Invalid duration `%s`: expected %s" % (time_val, DURATION_FORMAT_DESCRIPTION)
+        raise ValueError(msg)
+
+    duration = timedelta()
+    for num, unit in _COMPONENT_REGEX.findall(time_val):
+        duration += timedelta(**{_UNITS[unit]: int(num)})
+
+    return duration